@@ -0,0 +1,159 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "math"
+
+// JitterStrategy selects how a Backoff randomizes its delays. The strategies
+// are those described in "Exponential Backoff And Jitter" (Brooker, 2015).
+type JitterStrategy int
+
+const (
+	// JitterNone uses the exponential delay as-is, which is deterministic.
+	JitterNone JitterStrategy = iota
+	// JitterFull chooses a delay uniformly from [0, d], where d is the
+	// exponential delay.
+	JitterFull
+	// JitterEqual chooses a delay uniformly from [d/2, d], where d is the
+	// exponential delay.
+	JitterEqual
+	// JitterDecorrelated chooses a delay uniformly from [Base, p*Factor],
+	// where p is the previous delay, so that each delay depends on the last
+	// rather than on the number of attempts.
+	JitterDecorrelated
+)
+
+// Backoff calculates exponential backoff delays for retries, for any duration
+// type based on int64 such as time.Duration. The exponential delay for the
+// n-th call to Next (starting from 0) is Base*Factor^n, capped at Max and then
+// randomized according to Jitter.
+//
+// Delays are deterministic when Jitter is JitterNone, or when Source is a
+// seeded RNG. Backoff is not safe for concurrent use.
+type Backoff[T ~int64] struct {
+	// Base is the initial delay. If it is not positive, every delay is 0.
+	Base T
+	// Max is the largest delay returned. If it is not positive, delays are
+	// capped at the maximum value of T.
+	Max T
+	// Factor is the multiplier applied to the delay after each attempt. If it
+	// is less than 1 (including if it is unset), 2 is used.
+	Factor float64
+	// Jitter is the randomization strategy. The zero value is JitterNone.
+	Jitter JitterStrategy
+	// Source is used to randomize delays. If it is nil, FastSource is used.
+	Source Source
+
+	attempt int
+	prev    T
+}
+
+// Next returns the delay before the next retry.
+func (b *Backoff[T]) Next() T {
+	if b.Base <= 0 {
+		b.attempt++
+		return 0
+	}
+
+	var (
+		base   = b.Base
+		limit  = b.limit()
+		factor = b.factor()
+		src    = sourceOrFast(b.Source)
+	)
+
+	if base > limit {
+		base = limit
+	}
+
+	var d T
+	switch b.Jitter {
+	case JitterFull:
+		d = IntRange(src, 0, b.delay(base, limit, factor))
+	case JitterEqual:
+		exp := b.delay(base, limit, factor)
+		d = IntRange(src, exp-exp/2, exp)
+	case JitterDecorrelated:
+		prev := b.prev
+		if prev < base {
+			prev = base
+		}
+		hi := ConvertSat[T](float64(prev) * factor)
+		d = Clamp(IntRange(src, base, Clamp(hi, base, limit)), base, limit)
+	default:
+		d = b.delay(base, limit, factor)
+	}
+
+	b.attempt++
+	b.prev = d
+	return d
+}
+
+// Attempt returns the number of times Next has been called since b was created
+// or last reset.
+func (b *Backoff[T]) Attempt() int {
+	return b.attempt
+}
+
+// Reset resets b to its initial delay.
+func (b *Backoff[T]) Reset() {
+	b.attempt = 0
+	b.prev = 0
+}
+
+// delay returns the exponential delay for the current attempt, in [base,
+// limit].
+func (b *Backoff[T]) delay(base T, limit T, factor float64) T {
+	exp := float64(base) * math.Pow(factor, float64(b.attempt))
+	return Clamp(ConvertSat[T](exp), base, limit)
+}
+
+func (b *Backoff[T]) limit() T {
+	if b.Max <= 0 {
+		return MaxValue[T]()
+	}
+	return b.Max
+}
+
+func (b *Backoff[T]) factor() float64 {
+	if !(b.Factor >= 1) {
+		return 2
+	}
+	return b.Factor
+}
+
+// Jitter returns d randomized uniformly by up to the given fraction of d in
+// either direction using src, or FastSource if src is nil; e.g. a fraction of
+// 0.1 returns a value in [0.9*d, 1.1*d]. fraction is clamped to [0, 1], and
+// the result saturates at the bounds of T.
+func Jitter[T ~int64](src Source, d T, fraction float64) T {
+	if !(fraction > 0) {
+		return d
+	}
+
+	var (
+		delta = ConvertSat[T](math.Abs(float64(d)) * math.Min(fraction, 1))
+		lo    = SubSat(d, delta)
+		hi    = AddSat(d, delta)
+	)
+
+	return IntRange(sourceOrFast(src), lo, hi)
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+type millis int64
+
+func TestBackoffNoJitter(t *testing.T) {
+	b := math.Backoff[time.Duration]{
+		Base: 100 * time.Millisecond,
+		Max:  time.Second,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, w := range want {
+		require.Equal(t, i, b.Attempt())
+		require.Equal(t, w, b.Next())
+	}
+
+	b.Reset()
+	require.Equal(t, 0, b.Attempt())
+	require.Equal(t, 100*time.Millisecond, b.Next())
+}
+
+func TestBackoffFactor(t *testing.T) {
+	b := math.Backoff[millis]{Base: 10, Factor: 1.5}
+	for _, want := range []millis{10, 15, 22, 33, 50} {
+		require.Equal(t, want, b.Next())
+	}
+
+	// Factors below 1 fall back to the default of 2.
+	b = math.Backoff[millis]{Base: 10, Factor: 0.5}
+	for _, want := range []millis{10, 20, 40} {
+		require.Equal(t, want, b.Next())
+	}
+
+	b = math.Backoff[millis]{Base: 10, Factor: stdmath.NaN()}
+	for _, want := range []millis{10, 20, 40} {
+		require.Equal(t, want, b.Next())
+	}
+
+	// A factor of 1 produces a constant delay.
+	b = math.Backoff[millis]{Base: 10, Factor: 1}
+	for i := 0; i < 5; i++ {
+		require.Equal(t, millis(10), b.Next())
+	}
+}
+
+func TestBackoffLimits(t *testing.T) {
+	// Without a cap, delays saturate rather than overflowing.
+	b := math.Backoff[time.Duration]{Base: time.Second, Factor: 10}
+	for i := 0; i < 100; i++ {
+		require.Greater(t, b.Next(), time.Duration(0))
+	}
+	require.Equal(t, time.Duration(stdmath.MaxInt64), b.Next())
+
+	b = math.Backoff[time.Duration]{Base: time.Second, Jitter: math.JitterDecorrelated}
+	for i := 0; i < 200; i++ {
+		require.GreaterOrEqual(t, b.Next(), time.Second)
+	}
+
+	// A base greater than the cap is capped.
+	b = math.Backoff[time.Duration]{Base: time.Minute, Max: time.Second}
+	require.Equal(t, time.Second, b.Next())
+
+	// A non-positive base disables delays.
+	b = math.Backoff[time.Duration]{Max: time.Second, Jitter: math.JitterFull}
+	require.Zero(t, b.Next())
+	require.Zero(t, b.Next())
+	require.Equal(t, 2, b.Attempt())
+}
+
+func TestBackoffFullJitter(t *testing.T) {
+	testBackoffJitter(t, math.JitterFull, func(exp millis) (millis, millis) {
+		return 0, exp
+	})
+}
+
+func TestBackoffEqualJitter(t *testing.T) {
+	testBackoffJitter(t, math.JitterEqual, func(exp millis) (millis, millis) {
+		return exp - exp/2, exp
+	})
+}
+
+func testBackoffJitter(
+	t *testing.T,
+	jitter math.JitterStrategy,
+	bounds func(exp millis) (millis, millis),
+) {
+	var (
+		rng   = math.NewRNG(1)
+		exps  = []millis{100, 200, 400, 800, 1000, 1000}
+		sums  = make([]float64, len(exps))
+		iters = 2000
+	)
+
+	for i := 0; i < iters; i++ {
+		b := math.Backoff[millis]{
+			Base:   100,
+			Max:    1000,
+			Jitter: jitter,
+			Source: rng,
+		}
+
+		for j, exp := range exps {
+			lo, hi := bounds(exp)
+			d := b.Next()
+			require.True(t, d >= lo && d <= hi, "%d not in [%d, %d]", d, lo, hi)
+			sums[j] += float64(d)
+		}
+	}
+
+	for j, exp := range exps {
+		lo, hi := bounds(exp)
+		require.InEpsilon(t, float64(lo+hi)/2, sums[j]/float64(iters), 0.05)
+	}
+}
+
+func TestBackoffDecorrelatedJitter(t *testing.T) {
+	b := math.Backoff[millis]{
+		Base:   100,
+		Max:    5000,
+		Factor: 3,
+		Jitter: math.JitterDecorrelated,
+		Source: math.NewRNG(2),
+	}
+
+	var (
+		prev    = millis(100)
+		longest millis
+	)
+
+	for i := 0; i < 1000; i++ {
+		d := b.Next()
+		require.GreaterOrEqual(t, d, millis(100))
+		require.LessOrEqual(t, d, math.Min(prev*3, 5000))
+		prev = d
+		longest = math.Max(longest, d)
+	}
+
+	// Delays should approach the cap.
+	require.Greater(t, longest, millis(4500))
+}
+
+func TestBackoffDeterministic(t *testing.T) {
+	for _, jitter := range []math.JitterStrategy{
+		math.JitterNone,
+		math.JitterFull,
+		math.JitterEqual,
+		math.JitterDecorrelated,
+	} {
+		var (
+			a = math.Backoff[time.Duration]{
+				Base:   time.Millisecond,
+				Max:    time.Minute,
+				Jitter: jitter,
+				Source: math.NewRNG(42),
+			}
+			b = a
+		)
+
+		b.Source = math.NewRNG(42)
+		for i := 0; i < 50; i++ {
+			require.Equal(t, a.Next(), b.Next())
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	rng := math.NewRNG(3)
+
+	for i := 0; i < 1000; i++ {
+		d := math.Jitter(rng, time.Second, 0.1)
+		require.True(t, d >= 900*time.Millisecond && d <= 1100*time.Millisecond, d)
+	}
+
+	testUniform(t, 21, distributionDraws, func() int {
+		return int(math.Jitter(rng, millis(100), 0.1) - 90)
+	})
+
+	require.Equal(t, time.Second, math.Jitter(rng, time.Second, 0))
+	require.Equal(t, time.Second, math.Jitter(rng, time.Second, -1))
+	require.Equal(t, time.Second, math.Jitter(rng, time.Second, stdmath.NaN()))
+
+	for i := 0; i < 1000; i++ {
+		d := math.Jitter(nil, time.Second, 5)
+		require.True(t, d >= 0 && d <= 2*time.Second, d)
+
+		d = math.Jitter(rng, -time.Second, 0.5)
+		require.True(t, d >= -1500*time.Millisecond && d <= -500*time.Millisecond, d)
+
+		d = math.Jitter(rng, time.Duration(stdmath.MaxInt64), 0.5)
+		require.Greater(t, d, time.Duration(0))
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// The functions in this file operate on the two's complement bit pattern of x
+// at the width of T, so that e.g. PopCount(int8(-1)) is 8 rather than 64.
+
+// PopCount returns the number of one bits ("population count") in x.
+func PopCount[T constraints.Integer](x T) int {
+	switch BitSize[T]() {
+	case 8:
+		return bits.OnesCount8(uint8(x))
+	case 16:
+		return bits.OnesCount16(uint16(x))
+	case 32:
+		return bits.OnesCount32(uint32(x))
+	default:
+		return bits.OnesCount64(uint64(x))
+	}
+}
+
+// LeadingZeros returns the number of leading zero bits in x. The result is
+// the bit size of T for x == 0.
+func LeadingZeros[T constraints.Integer](x T) int {
+	switch BitSize[T]() {
+	case 8:
+		return bits.LeadingZeros8(uint8(x))
+	case 16:
+		return bits.LeadingZeros16(uint16(x))
+	case 32:
+		return bits.LeadingZeros32(uint32(x))
+	default:
+		return bits.LeadingZeros64(uint64(x))
+	}
+}
+
+// TrailingZeros returns the number of trailing zero bits in x. The result is
+// the bit size of T for x == 0.
+func TrailingZeros[T constraints.Integer](x T) int {
+	switch BitSize[T]() {
+	case 8:
+		return bits.TrailingZeros8(uint8(x))
+	case 16:
+		return bits.TrailingZeros16(uint16(x))
+	case 32:
+		return bits.TrailingZeros32(uint32(x))
+	default:
+		return bits.TrailingZeros64(uint64(x))
+	}
+}
+
+// RotateLeft returns the value of x rotated left by (k mod the bit size of T)
+// bits. To rotate x right by k bits, call RotateLeft(x, -k).
+func RotateLeft[T constraints.Integer](x T, k int) T {
+	switch BitSize[T]() {
+	case 8:
+		return T(bits.RotateLeft8(uint8(x), k))
+	case 16:
+		return T(bits.RotateLeft16(uint16(x), k))
+	case 32:
+		return T(bits.RotateLeft32(uint32(x), k))
+	default:
+		return T(bits.RotateLeft64(uint64(x), k))
+	}
+}
+
+// Reverse returns the value of x with its bits in reversed order.
+func Reverse[T constraints.Integer](x T) T {
+	switch BitSize[T]() {
+	case 8:
+		return T(bits.Reverse8(uint8(x)))
+	case 16:
+		return T(bits.Reverse16(uint16(x)))
+	case 32:
+		return T(bits.Reverse32(uint32(x)))
+	default:
+		return T(bits.Reverse64(uint64(x)))
+	}
+}
+
+// ReverseBytes returns the value of x with its bytes in reversed order. For
+// single-byte types, x is returned unchanged.
+func ReverseBytes[T constraints.Integer](x T) T {
+	switch BitSize[T]() {
+	case 8:
+		return x
+	case 16:
+		return T(bits.ReverseBytes16(uint16(x)))
+	case 32:
+		return T(bits.ReverseBytes32(uint32(x)))
+	default:
+		return T(bits.ReverseBytes64(uint64(x)))
+	}
+}
+
+// Len returns the minimum number of bits required to represent x; the result
+// is 0 for x == 0. Negative values are treated as their two's complement bit
+// pattern, and so always require the full bit size of T.
+func Len[T constraints.Integer](x T) int {
+	switch BitSize[T]() {
+	case 8:
+		return bits.Len8(uint8(x))
+	case 16:
+		return bits.Len16(uint16(x))
+	case 32:
+		return bits.Len32(uint32(x))
+	default:
+		return bits.Len64(uint64(x))
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"math/bits"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+	"golang.org/x/exp/constraints"
+)
+
+type bitsOracle[U constraints.Unsigned] struct {
+	popCount      func(U) int
+	leadingZeros  func(U) int
+	trailingZeros func(U) int
+	rotateLeft    func(U, int) U
+	reverse       func(U) U
+	reverseBytes  func(U) U
+	len           func(U) int
+}
+
+var (
+	bits8 = bitsOracle[uint8]{
+		popCount:      bits.OnesCount8,
+		leadingZeros:  bits.LeadingZeros8,
+		trailingZeros: bits.TrailingZeros8,
+		rotateLeft:    bits.RotateLeft8,
+		reverse:       bits.Reverse8,
+		reverseBytes:  func(x uint8) uint8 { return x },
+		len:           bits.Len8,
+	}
+	bits16 = bitsOracle[uint16]{
+		popCount:      bits.OnesCount16,
+		leadingZeros:  bits.LeadingZeros16,
+		trailingZeros: bits.TrailingZeros16,
+		rotateLeft:    bits.RotateLeft16,
+		reverse:       bits.Reverse16,
+		reverseBytes:  bits.ReverseBytes16,
+		len:           bits.Len16,
+	}
+	bits32 = bitsOracle[uint32]{
+		popCount:      bits.OnesCount32,
+		leadingZeros:  bits.LeadingZeros32,
+		trailingZeros: bits.TrailingZeros32,
+		rotateLeft:    bits.RotateLeft32,
+		reverse:       bits.Reverse32,
+		reverseBytes:  bits.ReverseBytes32,
+		len:           bits.Len32,
+	}
+	bits64 = bitsOracle[uint64]{
+		popCount:      bits.OnesCount64,
+		leadingZeros:  bits.LeadingZeros64,
+		trailingZeros: bits.TrailingZeros64,
+		rotateLeft:    bits.RotateLeft64,
+		reverse:       bits.Reverse64,
+		reverseBytes:  bits.ReverseBytes64,
+		len:           bits.Len64,
+	}
+)
+
+func TestBits(t *testing.T) {
+	// 8- and 16-bit types are tested exhaustively.
+	testBits[int8](t, bits8, allValues[uint8]())
+	testBits[uint8](t, bits8, allValues[uint8]())
+	testBits[namedUint8](t, bits8, allValues[uint8]())
+	testBits[int16](t, bits16, allValues[uint16]())
+	testBits[uint16](t, bits16, allValues[uint16]())
+
+	testBits[int32](t, bits32, sampleValues[uint32]())
+	testBits[uint32](t, bits32, sampleValues[uint32]())
+	testBits[int64](t, bits64, sampleValues[uint64]())
+	testBits[uint64](t, bits64, sampleValues[uint64]())
+	testBits[time.Duration](t, bits64, sampleValues[uint64]())
+
+	if bits.UintSize == 32 {
+		testBits[int](t, bits32, sampleValues[uint32]())
+		testBits[uint](t, bits32, sampleValues[uint32]())
+		testBits[uintptr](t, bits32, sampleValues[uint32]())
+	} else {
+		testBits[int](t, bits64, sampleValues[uint64]())
+		testBits[uint](t, bits64, sampleValues[uint64]())
+		testBits[uintptr](t, bits64, sampleValues[uint64]())
+	}
+}
+
+func TestBitsSigned(t *testing.T) {
+	require.Equal(t, 8, math.PopCount[int8](-1))
+	require.Equal(t, 64, math.PopCount[int64](-1))
+	require.Equal(t, 0, math.LeadingZeros[int16](-1))
+	require.Equal(t, 15, math.TrailingZeros[int16](stdmath.MinInt16))
+	require.Equal(t, 32, math.Len[int32](-1))
+	require.Equal(t, int8(-128), math.RotateLeft[int8](1, -1))
+	require.Equal(t, int8(1), math.RotateLeft[int8](-128, 1))
+	require.Equal(t, int8(-128), math.Reverse[int8](1))
+	require.Equal(t, int16(-256), math.ReverseBytes[int16](0x00ff))
+}
+
+func testBits[T constraints.Integer, U constraints.Unsigned](
+	t *testing.T,
+	oracle bitsOracle[U],
+	values []U,
+) {
+	size := math.BitSize[T]()
+	require.Equal(t, math.BitSize[U](), size)
+
+	for _, u := range values {
+		x := T(u)
+
+		// Compare directly first; require is comparatively expensive when
+		// testing every value of a type.
+		if oracle.popCount(u) != math.PopCount(x) ||
+			oracle.leadingZeros(u) != math.LeadingZeros(x) ||
+			oracle.trailingZeros(u) != math.TrailingZeros(x) ||
+			T(oracle.reverse(u)) != math.Reverse(x) ||
+			T(oracle.reverseBytes(u)) != math.ReverseBytes(x) ||
+			oracle.len(u) != math.Len(x) {
+			require.Equal(t, oracle.popCount(u), math.PopCount(x), u)
+			require.Equal(t, oracle.leadingZeros(u), math.LeadingZeros(x), u)
+			require.Equal(t, oracle.trailingZeros(u), math.TrailingZeros(x), u)
+			require.Equal(t, T(oracle.reverse(u)), math.Reverse(x), u)
+			require.Equal(t, T(oracle.reverseBytes(u)), math.ReverseBytes(x), u)
+			require.Equal(t, oracle.len(u), math.Len(x), u)
+		}
+
+		for _, k := range []int{0, 1, 3, size - 1, size, size + 5, -1, -size - 3} {
+			if want := T(oracle.rotateLeft(u, k)); want != math.RotateLeft(x, k) {
+				require.Equal(t, want, math.RotateLeft(x, k), "%v <<< %d", u, k)
+			}
+		}
+	}
+}
+
+func allValues[U uint8 | uint16]() []U {
+	values := make([]U, 0, int(math.MaxValue[U]())+1)
+	for u := U(0); ; u++ {
+		values = append(values, u)
+		if u == math.MaxValue[U]() {
+			break
+		}
+	}
+	return values
+}
+
+func sampleValues[U uint32 | uint64]() []U {
+	var (
+		maxVal = math.MaxValue[U]()
+		values = []U{0, 1, 2, 3, maxVal, maxVal - 1, maxVal >> 1, maxVal>>1 + 1}
+	)
+
+	for shift := 0; shift < math.BitSize[U](); shift++ {
+		values = append(values, U(1)<<shift, maxVal<<shift, maxVal>>shift)
+	}
+
+	for i := 0; i < 10000; i++ {
+		values = append(
+			values,
+			U(uint64(math.Fastrand[uint32]())<<32|uint64(math.Fastrand[uint32]())),
+		)
+	}
+
+	return values
+}
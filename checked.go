@@ -0,0 +1,142 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "math"
+
+// AddChecked returns x+y, reporting whether the addition completed without
+// overflow. For floating point types, the addition is reported as failed if
+// it produces an infinity or NaN from finite operands.
+func AddChecked[T Numeric](x T, y T) (T, bool) {
+	z := x + y
+
+	switch {
+	case IsFloat[T]():
+		return z, checkFloat(z, x, y)
+	case y < 0:
+		return z, z < x
+	default:
+		return z, z >= x
+	}
+}
+
+// SubChecked returns x-y, reporting whether the subtraction completed without
+// overflow. For floating point types, the subtraction is reported as failed if
+// it produces an infinity or NaN from finite operands.
+func SubChecked[T Numeric](x T, y T) (T, bool) {
+	z := x - y
+
+	switch {
+	case IsFloat[T]():
+		return z, checkFloat(z, x, y)
+	case y < 0:
+		return z, z > x
+	default:
+		return z, z <= x
+	}
+}
+
+// MulChecked returns x*y, reporting whether the multiplication completed
+// without overflow. For floating point types, the multiplication is reported
+// as failed if it produces an infinity or NaN from finite operands.
+func MulChecked[T Numeric](x T, y T) (T, bool) {
+	if IsFloat[T]() {
+		z := x * y
+		return z, checkFloat(z, x, y)
+	}
+
+	if x == 0 || y == 0 {
+		return 0, true
+	}
+
+	if IsSigned[T]() {
+		// The only signed overflow that survives the division check below is
+		// the minimum value multiplied by -1, so handle -1 explicitly.
+		minusOne := -T(1)
+
+		switch {
+		case x == minusOne:
+			return NegChecked(y)
+		case y == minusOne:
+			return NegChecked(x)
+		}
+	}
+
+	z := x * y
+	return z, z/y == x
+}
+
+// DivChecked returns x/y, reporting whether the division completed without
+// overflow or division by zero. For floating point types, the division is
+// reported as failed if y is zero or if it produces an infinity or NaN from
+// finite operands.
+func DivChecked[T Numeric](x T, y T) (T, bool) {
+	if y == 0 {
+		return 0, false
+	}
+
+	switch {
+	case IsFloat[T]():
+		z := x / y
+		return z, checkFloat(z, x, y)
+	case IsSigned[T]() && y == -T(1):
+		return NegChecked(x)
+	default:
+		return x / y, true
+	}
+}
+
+// NegChecked returns -x, reporting whether the negation completed without
+// overflow. Negating a non-zero unsigned integer always overflows, as does
+// negating the minimum value of a signed integer type.
+func NegChecked[T Numeric](x T) (T, bool) {
+	z := -x
+
+	switch {
+	case IsFloat[T]():
+		return z, true
+	case !IsSigned[T]():
+		return z, x == 0
+	default:
+		return z, x == 0 || z != x
+	}
+}
+
+// checkFloat reports whether z is finite, or whether any of the operands that
+// produced it were not finite.
+func checkFloat[T Numeric](z T, operands ...T) bool {
+	if isFinite(z) {
+		return true
+	}
+
+	for _, x := range operands {
+		if !isFinite(x) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isFinite[T Numeric](x T) bool {
+	f := float64(x)
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"go.mway.dev/math"
+	"golang.org/x/exp/constraints"
+)
+
+func TestAddChecked(t *testing.T) {
+	testAddCheckedSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testAddCheckedSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testAddCheckedSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testAddCheckedSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testAddCheckedSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testAddCheckedSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testAddCheckedUnsigned[uint](t, stdmath.MaxUint)
+	testAddCheckedUnsigned[uint8](t, stdmath.MaxUint8)
+	testAddCheckedUnsigned[uint16](t, stdmath.MaxUint16)
+	testAddCheckedUnsigned[uint32](t, stdmath.MaxUint32)
+	testAddCheckedUnsigned[uint64](t, stdmath.MaxUint64)
+	testAddCheckedFloat[float32](t, stdmath.MaxFloat32)
+	testAddCheckedFloat[float64](t, stdmath.MaxFloat64)
+}
+
+func TestSubChecked(t *testing.T) {
+	testSubCheckedSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testSubCheckedSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testSubCheckedSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testSubCheckedSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testSubCheckedSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testSubCheckedSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testSubCheckedUnsigned[uint](t, stdmath.MaxUint)
+	testSubCheckedUnsigned[uint8](t, stdmath.MaxUint8)
+	testSubCheckedUnsigned[uint16](t, stdmath.MaxUint16)
+	testSubCheckedUnsigned[uint32](t, stdmath.MaxUint32)
+	testSubCheckedUnsigned[uint64](t, stdmath.MaxUint64)
+	testSubCheckedFloat[float32](t, stdmath.MaxFloat32)
+	testSubCheckedFloat[float64](t, stdmath.MaxFloat64)
+}
+
+func TestMulChecked(t *testing.T) {
+	testMulCheckedSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testMulCheckedSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testMulCheckedSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testMulCheckedSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testMulCheckedSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testMulCheckedSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testMulCheckedUnsigned[uint](t, stdmath.MaxUint)
+	testMulCheckedUnsigned[uint8](t, stdmath.MaxUint8)
+	testMulCheckedUnsigned[uint16](t, stdmath.MaxUint16)
+	testMulCheckedUnsigned[uint32](t, stdmath.MaxUint32)
+	testMulCheckedUnsigned[uint64](t, stdmath.MaxUint64)
+	testMulCheckedFloat[float32](t, stdmath.MaxFloat32)
+	testMulCheckedFloat[float64](t, stdmath.MaxFloat64)
+}
+
+func TestDivChecked(t *testing.T) {
+	testDivCheckedSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testDivCheckedSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testDivCheckedSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testDivCheckedSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testDivCheckedSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testDivCheckedSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testDivCheckedUnsigned[uint](t, stdmath.MaxUint)
+	testDivCheckedUnsigned[uint8](t, stdmath.MaxUint8)
+	testDivCheckedUnsigned[uint16](t, stdmath.MaxUint16)
+	testDivCheckedUnsigned[uint32](t, stdmath.MaxUint32)
+	testDivCheckedUnsigned[uint64](t, stdmath.MaxUint64)
+	testDivCheckedFloat[float32](t, stdmath.MaxFloat32)
+	testDivCheckedFloat[float64](t, stdmath.MaxFloat64)
+}
+
+func TestNegChecked(t *testing.T) {
+	testNegCheckedSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testNegCheckedSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testNegCheckedSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testNegCheckedSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testNegCheckedSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testNegCheckedSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testNegCheckedUnsigned[uint](t, stdmath.MaxUint)
+	testNegCheckedUnsigned[uint8](t, stdmath.MaxUint8)
+	testNegCheckedUnsigned[uint16](t, stdmath.MaxUint16)
+	testNegCheckedUnsigned[uint32](t, stdmath.MaxUint32)
+	testNegCheckedUnsigned[uint64](t, stdmath.MaxUint64)
+	testNegCheckedFloat[float32](t, stdmath.MaxFloat32)
+	testNegCheckedFloat[float64](t, stdmath.MaxFloat64)
+}
+
+func testAddCheckedSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	requireOK(t, T(3))(math.AddChecked[T](1, 2))
+	requireOK(t, T(-3))(math.AddChecked[T](-1, -2))
+	requireOK(t, T(-1))(math.AddChecked(minVal, maxVal))
+	requireOK(t, maxVal)(math.AddChecked(maxVal-1, 1))
+	requireOK(t, maxVal)(math.AddChecked(maxVal, 0))
+	requireOK(t, minVal)(math.AddChecked(minVal+1, -1))
+	requireOK(t, minVal)(math.AddChecked(minVal, 0))
+	requireNotOK[T](t)(math.AddChecked(maxVal, 1))
+	requireNotOK[T](t)(math.AddChecked(1, maxVal))
+	requireNotOK[T](t)(math.AddChecked(maxVal, maxVal))
+	requireNotOK[T](t)(math.AddChecked(minVal, -1))
+	requireNotOK[T](t)(math.AddChecked(-1, minVal))
+	requireNotOK[T](t)(math.AddChecked(minVal, minVal))
+}
+
+func testAddCheckedUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	requireOK(t, T(3))(math.AddChecked[T](1, 2))
+	requireOK(t, maxVal)(math.AddChecked(maxVal-1, 1))
+	requireOK(t, maxVal)(math.AddChecked(maxVal, 0))
+	requireOK(t, maxVal)(math.AddChecked(0, maxVal))
+	requireNotOK[T](t)(math.AddChecked(maxVal, 1))
+	requireNotOK[T](t)(math.AddChecked(1, maxVal))
+	requireNotOK[T](t)(math.AddChecked(maxVal, maxVal))
+}
+
+func testAddCheckedFloat[T constraints.Float](t *testing.T, maxVal T) {
+	requireOK(t, T(3.5))(math.AddChecked[T](1.25, 2.25))
+	requireOK(t, T(-3.5))(math.AddChecked[T](-1.25, -2.25))
+	requireOK(t, maxVal)(math.AddChecked(maxVal, 1))
+	requireOK(t, T(stdmath.Inf(1)))(math.AddChecked(T(stdmath.Inf(1)), 1))
+	requireNotOK[T](t)(math.AddChecked(maxVal, maxVal))
+	requireNotOK[T](t)(math.AddChecked(-maxVal, -maxVal))
+}
+
+func testSubCheckedSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	requireOK(t, T(-1))(math.SubChecked[T](1, 2))
+	requireOK(t, T(1))(math.SubChecked[T](-1, -2))
+	requireOK(t, T(0))(math.SubChecked(maxVal, maxVal))
+	requireOK(t, T(0))(math.SubChecked(minVal, minVal))
+	requireOK(t, maxVal)(math.SubChecked(maxVal-1, -1))
+	requireOK(t, minVal)(math.SubChecked(minVal+1, 1))
+	requireOK(t, minVal+1)(math.SubChecked(0, maxVal))
+	requireOK(t, maxVal)(math.SubChecked(-1, minVal))
+	requireNotOK[T](t)(math.SubChecked(maxVal, -1))
+	requireNotOK[T](t)(math.SubChecked(minVal, 1))
+	requireNotOK[T](t)(math.SubChecked(0, minVal))
+	requireNotOK[T](t)(math.SubChecked(maxVal, minVal))
+	requireNotOK[T](t)(math.SubChecked(minVal, maxVal))
+}
+
+func testSubCheckedUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	requireOK(t, T(1))(math.SubChecked[T](2, 1))
+	requireOK(t, T(0))(math.SubChecked(maxVal, maxVal))
+	requireOK(t, maxVal-1)(math.SubChecked(maxVal, 1))
+	requireOK(t, T(0))(math.SubChecked[T](0, 0))
+	requireNotOK[T](t)(math.SubChecked[T](1, 2))
+	requireNotOK[T](t)(math.SubChecked(0, maxVal))
+	requireNotOK[T](t)(math.SubChecked(maxVal-1, maxVal))
+}
+
+func testSubCheckedFloat[T constraints.Float](t *testing.T, maxVal T) {
+	requireOK(t, T(-1))(math.SubChecked[T](1.25, 2.25))
+	requireOK(t, T(0))(math.SubChecked(maxVal, maxVal))
+	requireOK(t, T(stdmath.Inf(-1)))(math.SubChecked(1, T(stdmath.Inf(1))))
+	requireNotOK[T](t)(math.SubChecked(maxVal, -maxVal))
+	requireNotOK[T](t)(math.SubChecked(-maxVal, maxVal))
+}
+
+func testMulCheckedSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	requireOK(t, T(6))(math.MulChecked[T](2, 3))
+	requireOK(t, T(-6))(math.MulChecked[T](-2, 3))
+	requireOK(t, T(6))(math.MulChecked[T](-2, -3))
+	requireOK(t, T(0))(math.MulChecked(maxVal, 0))
+	requireOK(t, T(0))(math.MulChecked(0, minVal))
+	requireOK(t, maxVal)(math.MulChecked(maxVal, 1))
+	requireOK(t, minVal)(math.MulChecked(minVal, 1))
+	requireOK(t, -maxVal)(math.MulChecked(maxVal, -1))
+	requireOK(t, -maxVal)(math.MulChecked(-1, maxVal))
+	requireOK(t, minVal)(math.MulChecked(minVal/2, 2))
+	requireOK(t, maxVal-1)(math.MulChecked(maxVal/2, 2))
+	requireNotOK[T](t)(math.MulChecked(minVal, -1))
+	requireNotOK[T](t)(math.MulChecked(-1, minVal))
+	requireNotOK[T](t)(math.MulChecked(maxVal, 2))
+	requireNotOK[T](t)(math.MulChecked(minVal, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, -2))
+	requireNotOK[T](t)(math.MulChecked(minVal/2-1, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal/2+1, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, maxVal))
+	requireNotOK[T](t)(math.MulChecked(minVal, minVal))
+}
+
+func testMulCheckedUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	requireOK(t, T(6))(math.MulChecked[T](2, 3))
+	requireOK(t, T(0))(math.MulChecked(maxVal, 0))
+	requireOK(t, maxVal)(math.MulChecked(maxVal, 1))
+	requireOK(t, maxVal-1)(math.MulChecked(maxVal/2, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal/2+1, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, maxVal))
+}
+
+func testMulCheckedFloat[T constraints.Float](t *testing.T, maxVal T) {
+	requireOK(t, T(3.75))(math.MulChecked[T](1.5, 2.5))
+	requireOK(t, -maxVal)(math.MulChecked(maxVal, -1))
+	requireOK(t, T(stdmath.Inf(1)))(math.MulChecked(T(stdmath.Inf(1)), 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, 2))
+	requireNotOK[T](t)(math.MulChecked(maxVal, -maxVal))
+}
+
+func testDivCheckedSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	requireOK(t, T(3))(math.DivChecked[T](7, 2))
+	requireOK(t, T(-3))(math.DivChecked[T](-7, 2))
+	requireOK(t, T(-3))(math.DivChecked[T](7, -2))
+	requireOK(t, T(3))(math.DivChecked[T](-7, -2))
+	requireOK(t, T(1))(math.DivChecked(maxVal, maxVal))
+	requireOK(t, T(1))(math.DivChecked(minVal, minVal))
+	requireOK(t, -maxVal)(math.DivChecked(maxVal, -1))
+	requireOK(t, minVal)(math.DivChecked(minVal, 1))
+	requireOK(t, T(0))(math.DivChecked(0, minVal))
+	requireNotOK[T](t)(math.DivChecked(minVal, -1))
+	requireNotOK[T](t)(math.DivChecked(maxVal, 0))
+	requireNotOK[T](t)(math.DivChecked(minVal, 0))
+	requireNotOK[T](t)(math.DivChecked[T](0, 0))
+}
+
+func testDivCheckedUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	requireOK(t, T(3))(math.DivChecked[T](7, 2))
+	requireOK(t, T(1))(math.DivChecked(maxVal, maxVal))
+	requireOK(t, maxVal)(math.DivChecked(maxVal, 1))
+	requireOK(t, T(0))(math.DivChecked(0, maxVal))
+	requireNotOK[T](t)(math.DivChecked(maxVal, 0))
+	requireNotOK[T](t)(math.DivChecked[T](0, 0))
+}
+
+func testDivCheckedFloat[T constraints.Float](t *testing.T, maxVal T) {
+	requireOK(t, T(3.5))(math.DivChecked[T](7, 2))
+	requireOK(t, T(-1))(math.DivChecked(maxVal, -maxVal))
+	requireOK(t, T(0))(math.DivChecked(1, T(stdmath.Inf(1))))
+	requireNotOK[T](t)(math.DivChecked(maxVal, 0.5))
+	requireNotOK[T](t)(math.DivChecked[T](1, 0))
+	requireNotOK[T](t)(math.DivChecked[T](0, 0))
+}
+
+func testNegCheckedSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	requireOK(t, T(0))(math.NegChecked[T](0))
+	requireOK(t, T(-1))(math.NegChecked[T](1))
+	requireOK(t, T(1))(math.NegChecked[T](-1))
+	requireOK(t, -maxVal)(math.NegChecked(maxVal))
+	requireOK(t, maxVal)(math.NegChecked(minVal + 1))
+	requireNotOK[T](t)(math.NegChecked(minVal))
+}
+
+func testNegCheckedUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	requireOK(t, T(0))(math.NegChecked[T](0))
+	requireNotOK[T](t)(math.NegChecked[T](1))
+	requireNotOK[T](t)(math.NegChecked(maxVal))
+}
+
+func testNegCheckedFloat[T constraints.Float](t *testing.T, maxVal T) {
+	requireOK(t, T(0))(math.NegChecked[T](0))
+	requireOK(t, T(-1.5))(math.NegChecked[T](1.5))
+	requireOK(t, -maxVal)(math.NegChecked(maxVal))
+	requireOK(t, maxVal)(math.NegChecked(-maxVal))
+	requireOK(t, T(stdmath.Inf(-1)))(math.NegChecked(T(stdmath.Inf(1))))
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNaN indicates that a NaN cannot be represented by the target type.
+	ErrNaN = errors.New("math: NaN is not representable")
+	// ErrInf indicates that an infinity cannot be represented by the target
+	// type.
+	ErrInf = errors.New("math: infinity is not representable")
+	// ErrSignLoss indicates that a negative value cannot be represented by an
+	// unsigned target type.
+	ErrSignLoss = errors.New("math: negative value is not representable")
+	// ErrOverflow indicates that a value is outside of the range of the target
+	// type.
+	ErrOverflow = errors.New("math: value out of range")
+	// ErrFractional indicates that a value with a fractional part cannot be
+	// represented by an integer target type.
+	ErrFractional = errors.New("math: fractional value is not representable")
+	// ErrPrecision indicates that a value cannot be represented exactly by a
+	// floating point target type.
+	ErrPrecision = errors.New("math: value is not exactly representable")
+)
+
+// Convert converts x from one Numeric type to another, returning an error if
+// the conversion would lose information. The returned error is one of ErrNaN,
+// ErrInf, ErrSignLoss, ErrOverflow, ErrFractional, or ErrPrecision, checked in
+// that order. NaN and infinities are preserved when converting between
+// floating point types.
+func Convert[To Numeric, From Numeric](x From) (To, error) {
+	switch {
+	case IsFloat[From]():
+		return convertFloat64[To](float64(x))
+	case IsSigned[From]():
+		return convertInt64[To](int64(x))
+	default:
+		return convertUint64[To](uint64(x))
+	}
+}
+
+// MustConvert is like Convert, but panics if the conversion would lose
+// information.
+func MustConvert[To Numeric, From Numeric](x From) To {
+	y, err := Convert[To](x)
+	if err != nil {
+		panic(err)
+	}
+
+	return y
+}
+
+// ConvertSat converts x from one Numeric type to another, saturating instead
+// of failing: values outside of the range of To (including infinities) are
+// clamped to MinValue or MaxValue, NaN becomes 0 for integer types (and is
+// preserved for floating point types), fractional values are truncated toward
+// zero, and inexact floating point values are rounded to the nearest
+// representable value.
+func ConvertSat[To Numeric, From Numeric](x From) To {
+	y, err := Convert[To](x)
+	if err == nil && math.IsInf(float64(y), 0) {
+		// Convert preserves infinities between floating point types, but they
+		// saturate like any other out of range value here.
+		err = ErrOverflow
+	}
+
+	switch err {
+	case nil:
+		return y
+	case ErrFractional, ErrPrecision:
+		return To(x)
+	case ErrNaN:
+		return 0
+	default:
+		if x < 0 {
+			return MinValue[To]()
+		}
+
+		return MaxValue[To]()
+	}
+}
+
+func convertFloat64[To Numeric](f float64) (To, error) {
+	if IsFloat[To]() {
+		return convertFloat64ToFloat[To](f)
+	}
+
+	// The exclusive upper bound is a power of two. For 64-bit types, MaxValue
+	// already rounds up to it as a float64, and adding 1 has no effect.
+	var (
+		signed = IsSigned[To]()
+		lo     = float64(MinValue[To]())
+		hi     = float64(MaxValue[To]()) + 1
+	)
+
+	switch {
+	case math.IsNaN(f):
+		return 0, ErrNaN
+	case math.IsInf(f, 0):
+		return 0, ErrInf
+	case f < 0 && !signed:
+		return 0, ErrSignLoss
+	case f < lo || f >= hi:
+		return 0, ErrOverflow
+	case f != math.Trunc(f):
+		return 0, ErrFractional
+	case signed:
+		return To(int64(f)), nil
+	default:
+		return To(uint64(f)), nil
+	}
+}
+
+func convertInt64[To Numeric](v int64) (To, error) {
+	if v < 0 && !IsSigned[To]() {
+		return 0, ErrSignLoss
+	}
+
+	y := To(v)
+
+	if IsFloat[To]() {
+		if f := float64(y); f >= 0x1p63 || int64(f) != v {
+			return 0, ErrPrecision
+		}
+
+		return y, nil
+	}
+
+	if int64(y) != v {
+		return 0, ErrOverflow
+	}
+
+	return y, nil
+}
+
+func convertUint64[To Numeric](u uint64) (To, error) {
+	y := To(u)
+
+	if IsFloat[To]() {
+		if f := float64(y); f >= 0x1p64 || uint64(f) != u {
+			return 0, ErrPrecision
+		}
+
+		return y, nil
+	}
+
+	if y < 0 || uint64(y) != u {
+		return 0, ErrOverflow
+	}
+
+	return y, nil
+}
+
+func convertFloat64ToFloat[To Numeric](f float64) (To, error) {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return To(f), nil
+	case BitSize[To]() == 64:
+		return To(f), nil
+	case math.Abs(f) > math.MaxFloat32:
+		return 0, ErrOverflow
+	case float64(float32(f)) != f:
+		return 0, ErrPrecision
+	default:
+		return To(f), nil
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"fmt"
+	stdmath "math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestConvert(t *testing.T) {
+	testConvertFrom[int](t)
+	testConvertFrom[int8](t)
+	testConvertFrom[int16](t)
+	testConvertFrom[int32](t)
+	testConvertFrom[int64](t)
+	testConvertFrom[uint](t)
+	testConvertFrom[uint8](t)
+	testConvertFrom[uint16](t)
+	testConvertFrom[uint32](t)
+	testConvertFrom[uint64](t)
+	testConvertFrom[float32](t)
+	testConvertFrom[float64](t)
+}
+
+func TestConvertErrors(t *testing.T) {
+	cases := []struct {
+		give    func() error
+		wantErr error
+	}{
+		{
+			give:    convertErr[int8](stdmath.NaN()),
+			wantErr: math.ErrNaN,
+		},
+		{
+			give:    convertErr[uint64](stdmath.Inf(1)),
+			wantErr: math.ErrInf,
+		},
+		{
+			give:    convertErr[uint32](-1),
+			wantErr: math.ErrSignLoss,
+		},
+		{
+			give:    convertErr[uint8](-0.5),
+			wantErr: math.ErrSignLoss,
+		},
+		{
+			give:    convertErr[int8](128),
+			wantErr: math.ErrOverflow,
+		},
+		{
+			give:    convertErr[int64](0x1p63),
+			wantErr: math.ErrOverflow,
+		},
+		{
+			give:    convertErr[float32](1e39),
+			wantErr: math.ErrOverflow,
+		},
+		{
+			give:    convertErr[int](1.5),
+			wantErr: math.ErrFractional,
+		},
+		{
+			give:    convertErr[float32](0.1),
+			wantErr: math.ErrPrecision,
+		},
+		{
+			give:    convertErr[float64](uint64(1<<53 + 1)),
+			wantErr: math.ErrPrecision,
+		},
+	}
+
+	for _, tt := range cases {
+		require.ErrorIs(t, tt.give(), tt.wantErr)
+	}
+}
+
+func TestMustConvert(t *testing.T) {
+	require.Equal(t, uint8(255), math.MustConvert[uint8](255.0))
+	require.Equal(t, int64(-1), math.MustConvert[int64](int8(-1)))
+	require.Equal(t, float32(0.5), math.MustConvert[float32](0.5))
+	require.PanicsWithValue(t, math.ErrOverflow, func() {
+		math.MustConvert[uint8](256)
+	})
+	require.PanicsWithValue(t, math.ErrSignLoss, func() {
+		math.MustConvert[uint](-1)
+	})
+}
+
+func TestConvertSat(t *testing.T) {
+	require.Equal(t, uint8(255), math.ConvertSat[uint8](1000))
+	require.Equal(t, uint8(0), math.ConvertSat[uint8](-1000))
+	require.Equal(t, int8(-128), math.ConvertSat[int8](-1000))
+	require.Equal(t, int8(2), math.ConvertSat[int8](2.9))
+	require.Equal(t, int8(-2), math.ConvertSat[int8](-2.9))
+	require.Equal(t, int64(0), math.ConvertSat[int64](stdmath.NaN()))
+	require.True(t, stdmath.IsNaN(float64(math.ConvertSat[float32](stdmath.NaN()))))
+	require.Equal(t, int64(stdmath.MaxInt64), math.ConvertSat[int64](stdmath.Inf(1)))
+	require.Equal(t, int64(stdmath.MinInt64), math.ConvertSat[int64](stdmath.Inf(-1)))
+	require.Equal(t, uint64(stdmath.MaxUint64), math.ConvertSat[uint64](1e30))
+	require.Equal(t, float32(stdmath.MaxFloat32), math.ConvertSat[float32](1e39))
+	require.Equal(t, float32(-stdmath.MaxFloat32), math.ConvertSat[float32](-1e39))
+	require.Equal(t, float32(0.1), math.ConvertSat[float32](0.1))
+	require.Equal(t, float32(stdmath.MaxFloat32), math.ConvertSat[float32](stdmath.Inf(1)))
+	require.Equal(t, -stdmath.MaxFloat64, math.ConvertSat[float64](stdmath.Inf(-1)))
+}
+
+func testConvertFrom[From math.Numeric](t *testing.T) {
+	testConvert[int, From](t)
+	testConvert[int8, From](t)
+	testConvert[int16, From](t)
+	testConvert[int32, From](t)
+	testConvert[int64, From](t)
+	testConvert[uint, From](t)
+	testConvert[uint8, From](t)
+	testConvert[uint16, From](t)
+	testConvert[uint32, From](t)
+	testConvert[uint64, From](t)
+	testConvert[float32, From](t)
+	testConvert[float64, From](t)
+}
+
+func testConvert[To math.Numeric, From math.Numeric](t *testing.T) {
+	t.Run(fmt.Sprintf("%T to %T", From(0), To(0)), func(t *testing.T) {
+		for _, give := range convertValues[From]() {
+			var (
+				want, wantErr = convertOracle[To](give)
+				have, err     = math.Convert[To](give)
+				sat           = math.ConvertSat[To](give)
+				msg           = fmt.Sprintf("give %v", give)
+			)
+
+			require.Equal(t, wantErr, err, msg)
+
+			switch {
+			case wantErr != nil:
+				require.Zero(t, have, msg)
+				require.Equal(t, convertSatOracle[To](give, wantErr), sat, msg)
+			case stdmath.IsNaN(float64(want)):
+				require.True(t, stdmath.IsNaN(float64(have)), msg)
+				require.True(t, stdmath.IsNaN(float64(sat)), msg)
+			case stdmath.IsInf(float64(want), 0):
+				require.Equal(t, want, have, msg)
+				require.Equal(t, convertSatOracle[To](give, math.ErrOverflow), sat, msg)
+			default:
+				require.Equal(t, want, have, msg)
+				require.Equal(t, want, sat, msg)
+			}
+		}
+	})
+}
+
+// convertValues returns a set of interesting values of T, including the
+// bounds of every other Numeric type.
+func convertValues[T math.Numeric]() []T {
+	ints := []int64{
+		0, 1, -1, 2, -2,
+		stdmath.MaxInt8, stdmath.MaxInt8 + 1, stdmath.MinInt8, stdmath.MinInt8 - 1,
+		stdmath.MaxUint8, stdmath.MaxUint8 + 1,
+		stdmath.MaxInt16, stdmath.MaxInt16 + 1, stdmath.MinInt16, stdmath.MinInt16 - 1,
+		stdmath.MaxUint16, stdmath.MaxUint16 + 1,
+		stdmath.MaxInt32, stdmath.MaxInt32 + 1, stdmath.MinInt32, stdmath.MinInt32 - 1,
+		stdmath.MaxUint32, stdmath.MaxUint32 + 1,
+		1 << 53, 1<<53 + 1, -(1 << 53), -(1<<53 + 1),
+		stdmath.MaxInt64, stdmath.MinInt64,
+	}
+
+	floats := []float64{
+		0.5, -0.5, 1.5, -1.5, 0.1, 1e-45, 1e-300,
+		0x1p63, -0x1p63, 0x1p64, -0x1p64, 1e30, -1e30,
+		stdmath.MaxFloat32, -stdmath.MaxFloat32, 1e39, -1e39,
+		stdmath.MaxFloat64, -stdmath.MaxFloat64,
+		stdmath.Inf(1), stdmath.Inf(-1), stdmath.NaN(),
+	}
+
+	values := []T{
+		math.MinValue[T](),
+		math.MaxValue[T](),
+		math.MaxValue[T]() - 1,
+	}
+
+	for _, x := range ints {
+		values = append(values, T(x))
+	}
+
+	if math.IsFloat[T]() {
+		for _, x := range floats {
+			values = append(values, T(x))
+		}
+	} else {
+		for _, x := range []uint64{1 << 63, stdmath.MaxUint64} {
+			values = append(values, T(x))
+		}
+	}
+
+	return values
+}
+
+// convertOracle converts x exactly using math/big, independently of the
+// implementation of math.Convert.
+//
+//nolint:gocyclo
+func convertOracle[To math.Numeric, From math.Numeric](x From) (To, error) {
+	if f := float64(x); stdmath.IsNaN(f) || stdmath.IsInf(f, 0) {
+		switch {
+		case math.IsFloat[To]():
+			return To(f), nil
+		case stdmath.IsNaN(f):
+			return 0, math.ErrNaN
+		default:
+			return 0, math.ErrInf
+		}
+	}
+
+	r := toRat(x)
+
+	switch {
+	case r.Sign() < 0 && !math.IsSigned[To]():
+		return 0, math.ErrSignLoss
+	case r.Cmp(toRat(math.MinValue[To]())) < 0 || r.Cmp(toRat(math.MaxValue[To]())) > 0:
+		return 0, math.ErrOverflow
+	case !math.IsFloat[To]() && !r.IsInt():
+		return 0, math.ErrFractional
+	case math.IsFloat[To]() && math.BitSize[To]() == 32:
+		f, exact := r.Float32()
+		if !exact {
+			return 0, math.ErrPrecision
+		}
+		return To(f), nil
+	case math.IsFloat[To]():
+		f, exact := r.Float64()
+		if !exact {
+			return 0, math.ErrPrecision
+		}
+		return To(f), nil
+	case math.IsSigned[To]():
+		return To(r.Num().Int64()), nil
+	default:
+		return To(r.Num().Uint64()), nil
+	}
+}
+
+func convertSatOracle[To math.Numeric, From math.Numeric](x From, err error) To {
+	switch err {
+	case math.ErrNaN:
+		return 0
+	case math.ErrFractional:
+		r := toRat(x)
+		n := new(big.Int).Quo(r.Num(), r.Denom())
+		if math.IsSigned[To]() {
+			return To(n.Int64())
+		}
+		return To(n.Uint64())
+	case math.ErrPrecision:
+		return To(x)
+	default:
+		if x < 0 {
+			return math.MinValue[To]()
+		}
+		return math.MaxValue[To]()
+	}
+}
+
+func toRat[T math.Numeric](x T) *big.Rat {
+	switch {
+	case math.IsFloat[T]():
+		return new(big.Rat).SetFloat64(float64(x))
+	case math.IsSigned[T]():
+		return new(big.Rat).SetInt64(int64(x))
+	default:
+		return new(big.Rat).SetUint64(uint64(x))
+	}
+}
+
+func convertErr[To math.Numeric, From math.Numeric](x From) func() error {
+	return func() error {
+		_, err := math.Convert[To](x)
+		return err
+	}
+}
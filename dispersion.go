@@ -0,0 +1,106 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "math"
+
+// Variance returns the population variance of the given numbers, or NaN if no
+// numbers are given. It uses the corrected two-pass algorithm, which
+// compensates for rounding error in the computed mean.
+func Variance[T Numeric](x ...T) float64 {
+	m2, _, _ := centralMoments(x, false)
+	return m2
+}
+
+// SampleVariance returns the sample (Bessel-corrected) variance of the given
+// numbers, or NaN if fewer than two numbers are given.
+func SampleVariance[T Numeric](x ...T) float64 {
+	if len(x) < 2 {
+		return math.NaN()
+	}
+
+	size := float64(len(x))
+	return Variance(x...) * size / (size - 1)
+}
+
+// StdDev returns the population standard deviation of the given numbers, or NaN
+// if no numbers are given.
+func StdDev[T Numeric](x ...T) float64 {
+	return math.Sqrt(Variance(x...))
+}
+
+// Skewness returns the population skewness of the given numbers, or NaN if no
+// numbers are given or if all numbers are equal.
+func Skewness[T Numeric](x ...T) float64 {
+	m2, m3, _ := centralMoments(x, true)
+	return m3 / (m2 * math.Sqrt(m2))
+}
+
+// Kurtosis returns the population excess kurtosis of the given numbers (such
+// that a normal distribution has a kurtosis of 0), or NaN if no numbers are
+// given or if all numbers are equal.
+func Kurtosis[T Numeric](x ...T) float64 {
+	m2, _, m4 := centralMoments(x, true)
+	return m4/(m2*m2) - 3
+}
+
+// centralMoments returns the second, third, and fourth central moments of x
+// using a two-pass algorithm. The second moment is corrected by the residual
+// sum of deviations; the third and fourth moments are only computed if higher
+// is true. If every number in x is equal, the second moment is exactly 0 and
+// the third and fourth moments are NaN, because the deviations from a rounded
+// mean would otherwise leave them nonzero.
+func centralMoments[T Numeric](x []T, higher bool) (m2 float64, m3 float64, m4 float64) {
+	if len(x) == 0 {
+		nan := math.NaN()
+		return nan, nan, nan
+	}
+
+	var (
+		size  = float64(len(x))
+		comp  float64
+		equal = true
+	)
+
+	mean, _ := MeanWideFloat64(x...)
+
+	for _, n := range x {
+		equal = equal && n == x[0]
+
+		d := float64(n) - mean
+		comp += d
+		m2 += d * d
+
+		if higher {
+			d3 := d * d * d
+			m3 += d3
+			m4 += d3 * d
+		}
+	}
+
+	if equal {
+		nan := math.NaN()
+		return 0, nan, nan
+	}
+
+	m2 = (m2 - comp*comp/size) / size
+	return m2, m3 / size, m4 / size
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+var (
+	// Sample from which expected values were calculated by hand.
+	dispersionSample = []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	// Equivalent to the NIST StRD NumAcc4 dataset, which has a certified mean
+	// of 10000000.2 and sample standard deviation of 0.1.
+	numAcc4 = func() []float64 {
+		x := []float64{10000000.2}
+		for i := 0; i < 500; i++ {
+			x = append(x, 10000000.1, 10000000.3)
+		}
+		return x
+	}()
+)
+
+func TestVariance(t *testing.T) {
+	require.Equal(t, 4.0, math.Variance(dispersionSample...))
+	require.Equal(t, 4.0, math.Variance[int](2, 4, 4, 4, 5, 5, 7, 9))
+	require.Equal(t, 4.0, math.Variance[uint8](2, 4, 4, 4, 5, 5, 7, 9))
+	require.Equal(t, 4.0, math.Variance[time.Duration](2, 4, 4, 4, 5, 5, 7, 9))
+	require.Equal(t, 4.0, math.Variance[float32](2, 4, 4, 4, 5, 5, 7, 9))
+	require.Equal(t, 0.0, math.Variance(10))
+	require.Equal(t, 0.0, math.Variance[uint8](200, 200, 200, 200))
+	require.Equal(t, 0.0, math.Variance(0.1, 0.1, 0.1))
+	require.InDelta(t, 0.01*1000/1001, math.Variance(numAcc4...), 1e-9)
+	require.True(t, stdmath.IsNaN(math.Variance[int]()))
+}
+
+func TestSampleVariance(t *testing.T) {
+	require.InDelta(t, 32.0/7.0, math.SampleVariance(dispersionSample...), 1e-12)
+	require.InDelta(t, 32.0/7.0, math.SampleVariance[int](2, 4, 4, 4, 5, 5, 7, 9), 1e-12)
+	require.InDelta(t, 0.01, math.SampleVariance(numAcc4...), 1e-9)
+	require.True(t, stdmath.IsNaN(math.SampleVariance(10)))
+	require.True(t, stdmath.IsNaN(math.SampleVariance[int]()))
+}
+
+func TestStdDev(t *testing.T) {
+	require.Equal(t, 2.0, math.StdDev(dispersionSample...))
+	require.Equal(t, 2.0, math.StdDev[int16](2, 4, 4, 4, 5, 5, 7, 9))
+	require.InDelta(t, 0.1, stdmath.Sqrt(math.SampleVariance(numAcc4...)), 1e-8)
+	require.True(t, stdmath.IsNaN(math.StdDev[int]()))
+}
+
+func TestSkewness(t *testing.T) {
+	require.Equal(t, 0.65625, math.Skewness(dispersionSample...))
+	require.Equal(t, 0.65625, math.Skewness[int](2, 4, 4, 4, 5, 5, 7, 9))
+	require.Equal(t, -0.65625, math.Skewness[int](-2, -4, -4, -4, -5, -5, -7, -9))
+	require.InDelta(t, 0.0, math.Skewness(numAcc4...), 1e-5)
+	require.True(t, stdmath.IsNaN(math.Skewness(1, 1, 1)))
+	require.True(t, stdmath.IsNaN(math.Skewness(0.1, 0.1, 0.1)))
+	require.True(t, stdmath.IsNaN(math.Skewness[float32](0.1, 0.1, 0.1, 0.1)))
+	require.True(t, stdmath.IsNaN(math.Skewness[int]()))
+}
+
+func TestKurtosis(t *testing.T) {
+	require.Equal(t, -0.21875, math.Kurtosis(dispersionSample...))
+	require.Equal(t, -0.21875, math.Kurtosis[uint32](2, 4, 4, 4, 5, 5, 7, 9))
+	require.InDelta(t, 1.001-3, math.Kurtosis(numAcc4...), 1e-6)
+	require.True(t, stdmath.IsNaN(math.Kurtosis(1, 1, 1)))
+	require.True(t, stdmath.IsNaN(math.Kurtosis(0.1, 0.1, 0.1)))
+	require.True(t, stdmath.IsNaN(math.Kurtosis[float32](0.1, 0.1, 0.1, 0.1)))
+	require.True(t, stdmath.IsNaN(math.Kurtosis[int]()))
+}
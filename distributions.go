@@ -0,0 +1,364 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// The samplers in this file draw from src, or from FastSource if src is nil.
+// Continuous samplers return NaN, and discrete samplers return 0, if their
+// parameters are invalid.
+
+// Normal returns a normally distributed T with the given mean and standard
+// deviation.
+func Normal[T constraints.Float](src Source, mean T, stddev T) T {
+	if !(stddev >= 0) {
+		return T(math.NaN())
+	}
+
+	return T(float64(mean) + float64(stddev)*stdNormal(sourceOrFast(src)))
+}
+
+// LogNormal returns a T whose natural logarithm is normally distributed with
+// the given mean mu and standard deviation sigma.
+func LogNormal[T constraints.Float](src Source, mu T, sigma T) T {
+	if !(sigma >= 0) {
+		return T(math.NaN())
+	}
+
+	return T(math.Exp(float64(mu) + float64(sigma)*stdNormal(sourceOrFast(src))))
+}
+
+// Exponential returns an exponentially distributed T with the given rate
+// (lambda), which must be positive. The mean of the distribution is 1/rate.
+func Exponential[T constraints.Float](src Source, rate T) T {
+	if !(rate > 0) {
+		return T(math.NaN())
+	}
+
+	return T(-math.Log(openUniform(sourceOrFast(src))) / float64(rate))
+}
+
+// Pareto returns a Pareto distributed T with the given scale (the minimum
+// possible value) and shape (alpha), both of which must be positive.
+func Pareto[T constraints.Float](src Source, scale T, shape T) T {
+	if !(scale > 0) || !(shape > 0) {
+		return T(math.NaN())
+	}
+
+	u := openUniform(sourceOrFast(src))
+	return T(float64(scale) * math.Pow(u, -1/float64(shape)))
+}
+
+// Gamma returns a gamma distributed T with the given shape (k) and scale
+// (theta), both of which must be positive. The mean of the distribution is
+// shape*scale.
+func Gamma[T constraints.Float](src Source, shape T, scale T) T {
+	if !(shape > 0) || !(scale > 0) {
+		return T(math.NaN())
+	}
+
+	return T(stdGamma(sourceOrFast(src), float64(shape)) * float64(scale))
+}
+
+// Beta returns a beta distributed T in [0, 1] with the given shape parameters
+// alpha and beta, both of which must be positive.
+func Beta[T constraints.Float](src Source, alpha T, beta T) T {
+	if !(alpha > 0) || !(beta > 0) {
+		return T(math.NaN())
+	}
+
+	return T(stdBeta(sourceOrFast(src), float64(alpha), float64(beta)))
+}
+
+// Poisson returns a Poisson distributed count with the given mean (lambda),
+// which must not be negative.
+func Poisson[T constraints.Float](src Source, lambda T) int {
+	l := float64(lambda)
+	if !(l > 0) || math.IsInf(l, 1) {
+		return 0
+	}
+
+	src = sourceOrFast(src)
+	if l < 10 {
+		return poissonInversion(src, l)
+	}
+
+	return poissonPTRS(src, l)
+}
+
+// Binomial returns the number of successes in n independent trials that each
+// succeed with probability p, which must be in [0, 1].
+func Binomial[T constraints.Float](src Source, n int, p T) int {
+	prob := float64(p)
+	switch {
+	case n <= 0 || !(prob >= 0 && prob <= 1):
+		return 0
+	case prob == 0:
+		return 0
+	case prob == 1:
+		return n
+	}
+
+	src = sourceOrFast(src)
+
+	// The i-th smallest of n uniform values is Beta(i, n+1-i) distributed, so
+	// one beta variate partitions the trials into those known to succeed or
+	// fail and a smaller binomial problem over the rest, conditioned on where
+	// the split fell.
+	var successes int
+	for n > binomialDirectMax {
+		var (
+			i = 1 + n/2
+			x = stdBeta(src, float64(i), float64(n+1-i))
+		)
+
+		if x >= prob {
+			n = i - 1
+			prob /= x
+		} else {
+			successes += i
+			n -= i
+			prob = (prob - x) / (1 - x)
+		}
+	}
+
+	for ; n > 0; n-- {
+		if Float[float64](src) < prob {
+			successes++
+		}
+	}
+
+	return successes
+}
+
+// Geometric returns the number of failed trials before the first success,
+// where each independent trial succeeds with probability p, which must be in
+// (0, 1].
+func Geometric[T constraints.Float](src Source, p T) int {
+	prob := float64(p)
+	if !(prob > 0 && prob <= 1) {
+		return 0
+	}
+
+	if prob == 1 {
+		return 0
+	}
+
+	u := openUniform(sourceOrFast(src))
+	return ConvertSat[int](math.Floor(math.Log(u) / math.Log1p(-prob)))
+}
+
+// Zipf returns a Zipf distributed value in [1, n], where the probability of k
+// is proportional to 1/k^s. n must be positive and s must be positive.
+func Zipf[T constraints.Float](src Source, s T, n int) int {
+	exp := float64(s)
+	if n < 1 || !(exp > 0) || math.IsInf(exp, 1) {
+		return 0
+	}
+
+	return zipfRejectionInversion(sourceOrFast(src), exp, n)
+}
+
+// binomialDirectMax is the largest number of trials that Binomial simulates
+// directly rather than splitting.
+const binomialDirectMax = 64
+
+func sourceOrFast(src Source) Source {
+	if src == nil {
+		return FastSource
+	}
+	return src
+}
+
+// openUniform returns a uniformly distributed float64 in (0, 1], which is safe
+// to pass to math.Log.
+func openUniform(src Source) float64 {
+	return 1 - Float[float64](src)
+}
+
+// stdNormal returns a standard normal variate using the Marsaglia polar
+// method.
+func stdNormal(src Source) float64 {
+	for {
+		var (
+			u = 2*Float[float64](src) - 1
+			v = 2*Float[float64](src) - 1
+			s = u*u + v*v
+		)
+
+		if s > 0 && s < 1 {
+			return u * math.Sqrt(-2*math.Log(s)/s)
+		}
+	}
+}
+
+// stdGamma returns a gamma variate with the given shape and a scale of 1,
+// using the method of Marsaglia and Tsang (2000).
+func stdGamma(src Source, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape above 1, then correct with a uniform power.
+		u := openUniform(src)
+		return stdGamma(src, shape+1) * math.Pow(u, 1/shape)
+	}
+
+	var (
+		d = shape - 1.0/3
+		c = 1 / math.Sqrt(9*d)
+	)
+
+	for {
+		x := stdNormal(src)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+
+		v = v * v * v
+		u := openUniform(src)
+		x2 := x * x
+
+		if u < 1-0.0331*x2*x2 || math.Log(u) < 0.5*x2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// stdBeta returns a beta variate as the ratio of two gamma variates.
+func stdBeta(src Source, alpha float64, beta float64) float64 {
+	x := stdGamma(src, alpha)
+	y := stdGamma(src, beta)
+	return x / (x + y)
+}
+
+// poissonInversion samples by sequential search of the CDF, which is efficient
+// for small lambda.
+func poissonInversion(src Source, lambda float64) int {
+	var (
+		k = 0
+		p = math.Exp(-lambda)
+		s = p
+		u = Float[float64](src)
+	)
+
+	for u > s && p > 0 {
+		k++
+		p *= lambda / float64(k)
+		s += p
+	}
+
+	return k
+}
+
+// poissonPTRS samples using the transformed rejection method with squeeze
+// described by Hörmann (1993), which has a constant expected cost.
+func poissonPTRS(src Source, lambda float64) int {
+	var (
+		slam     = math.Sqrt(lambda)
+		loglam   = math.Log(lambda)
+		b        = 0.931 + 2.53*slam
+		a        = -0.059 + 0.02483*b
+		invalpha = 1.1239 + 1.1328/(b-3.4)
+		vr       = 0.9277 - 3.6224/(b-2)
+	)
+
+	for {
+		var (
+			u  = Float[float64](src) - 0.5
+			v  = openUniform(src)
+			us = 0.5 - math.Abs(u)
+			k  = math.Floor((2*a/us+b)*u + lambda + 0.43)
+		)
+
+		if us >= 0.07 && v <= vr {
+			return ConvertSat[int](k)
+		}
+
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -lambda+k*loglam-lg {
+			return ConvertSat[int](k)
+		}
+	}
+}
+
+// zipfRejectionInversion samples using the rejection-inversion method of
+// Hörmann and Derflinger (1996), which supports any positive exponent.
+func zipfRejectionInversion(src Source, s float64, n int) int {
+	var (
+		// h is the unnormalized density, and hIntegral its antiderivative,
+		// (x^(1-s) - 1) / (1-s), computed stably for s near 1.
+		h = func(x float64) float64 {
+			return math.Exp(-s * math.Log(x))
+		}
+		hIntegral = func(x float64) float64 {
+			logX := math.Log(x)
+			return expm1x((1-s)*logX) * logX
+		}
+		hIntegralInverse = func(x float64) float64 {
+			t := x * (1 - s)
+			if t < -1 {
+				// Limit t to the domain of log1p; this only occurs due to
+				// rounding, and the result is clamped below regardless.
+				t = -1
+			}
+			return math.Exp(log1px(t) * x)
+		}
+
+		hIntegralX1 = hIntegral(1.5) - 1
+		hIntegralN  = hIntegral(float64(n) + 0.5)
+		squeeze     = 2 - hIntegralInverse(hIntegral(2.5)-h(2))
+	)
+
+	for {
+		var (
+			u = hIntegralN + Float[float64](src)*(hIntegralX1-hIntegralN)
+			x = hIntegralInverse(u)
+			k = Clamp(math.Floor(x+0.5), 1, float64(n))
+		)
+
+		if k-x <= squeeze || u >= hIntegral(k+0.5)-h(k) {
+			return int(k)
+		}
+	}
+}
+
+// log1px returns log1p(x)/x, which tends to 1 as x approaches 0.
+func log1px(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Log1p(x) / x
+	}
+	return 1 - x*(0.5-x*(1.0/3-0.25*x))
+}
+
+// expm1x returns expm1(x)/x, which tends to 1 as x approaches 0.
+func expm1x(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Expm1(x) / x
+	}
+	return 1 + x*0.5*(1+x/3*(1+0.25*x))
+}
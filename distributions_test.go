@@ -0,0 +1,356 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+const distributionDraws = 50000
+
+func TestNormal(t *testing.T) {
+	rng := math.NewRNG(1)
+	sample := func() float64 { return math.Normal(rng, 10.0, 2.0) }
+
+	testMoments(t, sample, 10, 4)
+	testKS(t, sample, func(x float64) float64 {
+		return 0.5 * stdmath.Erfc(-(x-10)/(2*stdmath.Sqrt2))
+	})
+
+	require.Equal(t, 3.0, math.Normal(rng, 3.0, 0))
+	require.True(t, stdmath.IsNaN(math.Normal(rng, 0, -1.0)))
+	require.True(t, stdmath.IsNaN(math.Normal(rng, 0, stdmath.NaN())))
+}
+
+func TestLogNormal(t *testing.T) {
+	var (
+		rng       = math.NewRNG(2)
+		mu, sigma = 1.0, 0.5
+		sample    = func() float64 { return math.LogNormal(rng, mu, sigma) }
+	)
+
+	testMoments(
+		t,
+		sample,
+		stdmath.Exp(mu+sigma*sigma/2),
+		(stdmath.Exp(sigma*sigma)-1)*stdmath.Exp(2*mu+sigma*sigma),
+	)
+	testKS(t, sample, func(x float64) float64 {
+		return 0.5 * stdmath.Erfc(-(stdmath.Log(x)-mu)/(sigma*stdmath.Sqrt2))
+	})
+
+	require.True(t, stdmath.IsNaN(math.LogNormal(rng, 0, -1.0)))
+}
+
+func TestExponential(t *testing.T) {
+	rng := math.NewRNG(3)
+	sample := func() float64 { return math.Exponential(rng, 0.5) }
+
+	testMoments(t, sample, 2, 4)
+	testKS(t, sample, func(x float64) float64 {
+		return 1 - stdmath.Exp(-0.5*x)
+	})
+
+	require.True(t, stdmath.IsNaN(math.Exponential(rng, 0.0)))
+	require.True(t, stdmath.IsNaN(math.Exponential(rng, -1.0)))
+}
+
+func TestPareto(t *testing.T) {
+	var (
+		rng          = math.NewRNG(4)
+		scale, shape = 2.0, 5.0
+		sample       = func() float64 { return math.Pareto(rng, scale, shape) }
+	)
+
+	testMoments(
+		t,
+		sample,
+		shape*scale/(shape-1),
+		scale*scale*shape/((shape-1)*(shape-1)*(shape-2)),
+	)
+	testKS(t, sample, func(x float64) float64 {
+		return 1 - stdmath.Pow(scale/x, shape)
+	})
+
+	require.True(t, stdmath.IsNaN(math.Pareto(rng, 0, 1.0)))
+	require.True(t, stdmath.IsNaN(math.Pareto(rng, 1.0, 0)))
+}
+
+func TestGamma(t *testing.T) {
+	rng := math.NewRNG(5)
+
+	// An Erlang distribution, with shape 3 and scale 2.
+	sample := func() float64 { return math.Gamma(rng, 3.0, 2.0) }
+	testMoments(t, sample, 6, 12)
+	testKS(t, sample, func(x float64) float64 {
+		y := x / 2
+		return 1 - stdmath.Exp(-y)*(1+y+y*y/2)
+	})
+
+	// A chi-square distribution with one degree of freedom, which exercises
+	// shapes less than 1.
+	sample = func() float64 { return math.Gamma(rng, 0.5, 2.0) }
+	testMoments(t, sample, 1, 2)
+	testKS(t, sample, func(x float64) float64 {
+		return stdmath.Erf(stdmath.Sqrt(x / 2))
+	})
+
+	require.True(t, stdmath.IsNaN(math.Gamma(rng, 0, 1.0)))
+	require.True(t, stdmath.IsNaN(math.Gamma(rng, 1.0, -1.0)))
+}
+
+func TestBeta(t *testing.T) {
+	rng := math.NewRNG(6)
+
+	sample := func() float64 { return math.Beta(rng, 2.0, 3.0) }
+	testMoments(t, sample, 0.4, 0.04)
+	testKS(t, sample, func(x float64) float64 {
+		// The regularized incomplete beta function for integer parameters.
+		return 6*x*x*(1-x)*(1-x) + 4*x*x*x*(1-x) + x*x*x*x
+	})
+
+	// The arcsine distribution.
+	sample = func() float64 { return math.Beta(rng, 0.5, 0.5) }
+	testMoments(t, sample, 0.5, 0.125)
+	testKS(t, sample, func(x float64) float64 {
+		return 2 / stdmath.Pi * stdmath.Asin(stdmath.Sqrt(x))
+	})
+
+	require.True(t, stdmath.IsNaN(math.Beta(rng, 0, 1.0)))
+	require.True(t, stdmath.IsNaN(math.Beta(rng, 1.0, 0)))
+}
+
+func TestPoisson(t *testing.T) {
+	rng := math.NewRNG(7)
+
+	for _, lambda := range []float64{0.5, 3, 9.9, 10, 50, 1000} {
+		lambda := lambda
+		sample := func() int { return math.Poisson(rng, lambda) }
+
+		testMoments(t, func() float64 { return float64(sample()) }, lambda, lambda)
+		testDiscrete(t, sample, 0, int(lambda*3)+10, func(k int) float64 {
+			lg, _ := stdmath.Lgamma(float64(k) + 1)
+			return stdmath.Exp(float64(k)*stdmath.Log(lambda) - lambda - lg)
+		})
+	}
+
+	require.Zero(t, math.Poisson(rng, 0.0))
+	require.Zero(t, math.Poisson(rng, -1.0))
+	require.Zero(t, math.Poisson(rng, stdmath.NaN()))
+	require.InDelta(t, 1e12, math.Poisson(rng, 1e12), 1e7)
+}
+
+func TestBinomial(t *testing.T) {
+	rng := math.NewRNG(8)
+
+	cases := []struct {
+		n int
+		p float64
+	}{
+		{1, 0.5},
+		{20, 0.3},
+		{64, 0.9},
+		{65, 0.5},
+		{1000, 0.4},
+		{500, 0.99},
+		{100000, 0.001},
+	}
+
+	for _, tt := range cases {
+		var (
+			n, p   = tt.n, tt.p
+			sample = func() int { return math.Binomial(rng, n, p) }
+		)
+
+		testMoments(t, func() float64 { return float64(sample()) }, float64(n)*p, float64(n)*p*(1-p))
+		testDiscrete(t, sample, 0, n, func(k int) float64 {
+			var (
+				a, _ = stdmath.Lgamma(float64(n) + 1)
+				b, _ = stdmath.Lgamma(float64(k) + 1)
+				c, _ = stdmath.Lgamma(float64(n-k) + 1)
+			)
+			return stdmath.Exp(
+				a - b - c + float64(k)*stdmath.Log(p) + float64(n-k)*stdmath.Log1p(-p),
+			)
+		})
+	}
+
+	require.Zero(t, math.Binomial(rng, 0, 0.5))
+	require.Zero(t, math.Binomial(rng, -1, 0.5))
+	require.Zero(t, math.Binomial(rng, 10, 0.0))
+	require.Zero(t, math.Binomial(rng, 10, 1.5))
+	require.Equal(t, 10, math.Binomial(rng, 10, 1.0))
+}
+
+func TestGeometric(t *testing.T) {
+	rng := math.NewRNG(9)
+
+	for _, p := range []float64{0.9, 0.2, 0.01} {
+		p := p
+		sample := func() int { return math.Geometric(rng, p) }
+
+		testMoments(t, func() float64 { return float64(sample()) }, (1-p)/p, (1-p)/(p*p))
+		testDiscrete(t, sample, 0, int(20/p), func(k int) float64 {
+			return stdmath.Pow(1-p, float64(k)) * p
+		})
+	}
+
+	require.Zero(t, math.Geometric(rng, 1.0))
+	require.Zero(t, math.Geometric(rng, 0.0))
+	require.Zero(t, math.Geometric(rng, 1.5))
+}
+
+func TestZipf(t *testing.T) {
+	rng := math.NewRNG(10)
+
+	cases := []struct {
+		s float64
+		n int
+	}{
+		{1.2, 10},
+		{1, 100},
+		{0.8, 1000},
+		{2, 1},
+		{3, 1000000},
+	}
+
+	for _, tt := range cases {
+		var (
+			s, n = tt.s, tt.n
+			norm float64
+		)
+
+		for k := 1; k <= n; k++ {
+			norm += stdmath.Pow(float64(k), -s)
+		}
+
+		testDiscrete(t, func() int { return math.Zipf(rng, s, n) }, 1, n, func(k int) float64 {
+			return stdmath.Pow(float64(k), -s) / norm
+		})
+	}
+
+	require.Zero(t, math.Zipf(rng, 1.0, 0))
+	require.Zero(t, math.Zipf(rng, 0.0, 10))
+	require.Zero(t, math.Zipf(rng, stdmath.NaN(), 10))
+}
+
+func TestDistributionsDefaultSource(t *testing.T) {
+	require.False(t, stdmath.IsNaN(math.Normal[float64](nil, 0, 1)))
+	require.Greater(t, math.Exponential[float32](nil, 1), float32(0))
+	require.GreaterOrEqual(t, math.Pareto[float32](nil, 1, 1), float32(1))
+	require.GreaterOrEqual(t, math.Poisson[float64](nil, 100), 0)
+	require.LessOrEqual(t, math.Binomial[float64](nil, 1000, 0.5), 1000)
+	require.GreaterOrEqual(t, math.Zipf[float64](nil, 1.5, 10), 1)
+}
+
+// testMoments asserts that the mean and variance of values drawn from sample
+// are consistent with the given population mean and variance.
+func testMoments(t *testing.T, sample func() float64, mean float64, variance float64) {
+	t.Helper()
+
+	var stats math.Stats[float64]
+	for i := 0; i < distributionDraws; i++ {
+		stats.Add(sample())
+	}
+
+	require.InDelta(t, mean, stats.Mean(), 5*stdmath.Sqrt(variance/distributionDraws))
+	require.InEpsilon(t, variance, stats.Variance(), 0.1)
+}
+
+// testKS asserts that values drawn from sample pass a Kolmogorov-Smirnov test
+// against the given CDF at a significance level of 0.001.
+func testKS(t *testing.T, sample func() float64, cdf func(float64) float64) {
+	t.Helper()
+
+	x := make([]float64, distributionDraws)
+	for i := range x {
+		x[i] = sample()
+	}
+	sort.Float64s(x)
+
+	var d float64
+	for i, v := range x {
+		f := cdf(v)
+		d = stdmath.Max(d, stdmath.Max(
+			f-float64(i)/distributionDraws,
+			float64(i+1)/distributionDraws-f,
+		))
+	}
+
+	require.Less(t, d, 1.95/stdmath.Sqrt(distributionDraws))
+}
+
+// testDiscrete asserts that values drawn from sample pass a chi-square test
+// against the given probability mass function, which is evaluated for every
+// value in [lo, hi]. Adjacent values are grouped so that every bin has an
+// expected count of at least 5, and the mass above hi forms the final bin.
+func testDiscrete(t *testing.T, sample func() int, lo int, hi int, pmf func(int) float64) {
+	t.Helper()
+
+	var (
+		starts   []int
+		expected []float64
+		acc      float64
+		start    = lo
+		total    float64
+	)
+
+	for k := lo; k <= hi; k++ {
+		acc += pmf(k) * distributionDraws
+		if acc >= 5 {
+			starts = append(starts, start)
+			expected = append(expected, acc)
+			total += acc
+			acc, start = 0, k+1
+		}
+	}
+
+	if tail := distributionDraws - total; tail >= 5 || len(expected) == 0 {
+		starts = append(starts, start)
+		expected = append(expected, tail)
+	} else {
+		expected[len(expected)-1] += tail
+	}
+
+	counts := make([]float64, len(expected))
+	for i := 0; i < distributionDraws; i++ {
+		x := sample()
+		require.GreaterOrEqual(t, x, lo)
+		counts[sort.SearchInts(starts, x+1)-1]++
+	}
+
+	if len(counts) == 1 {
+		return
+	}
+
+	var chi2 float64
+	for i, c := range counts {
+		d := c - expected[i]
+		chi2 += d * d / expected[i]
+	}
+
+	require.Less(t, chi2, chiSquareCritical(len(counts)-1))
+}
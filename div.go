@@ -0,0 +1,119 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "golang.org/x/exp/constraints"
+
+// The functions in this file mirror the behavior of Go's / and % operators
+// at their edges: they panic if y is zero, and dividing the minimum value of a
+// signed type by -1 wraps rather than panicking.
+
+// DivFloor returns x/y rounded toward negative infinity, e.g. DivFloor(-7, 2)
+// is -4 where -7/2 is -3.
+func DivFloor[T constraints.Integer](x T, y T) T {
+	q, r := x/y, x%y
+	if r != 0 && (r < 0) != (y < 0) {
+		q--
+	}
+	return q
+}
+
+// DivCeil returns x/y rounded toward positive infinity, e.g. DivCeil(7, 2) is
+// 4 and DivCeil(-7, 2) is -3.
+func DivCeil[T constraints.Integer](x T, y T) T {
+	q, r := x/y, x%y
+	if r != 0 && (r < 0) == (y < 0) {
+		q++
+	}
+	return q
+}
+
+// DivRound returns x/y rounded to the nearest integer, with ties rounded away
+// from zero, e.g. DivRound(7, 2) is 4 and DivRound(-7, 2) is -4.
+func DivRound[T constraints.Integer](x T, y T) T {
+	q, r := x/y, x%y
+	if r == 0 {
+		return q
+	}
+
+	// |r| < |y|, so neither negating r nor y+|r| can overflow.
+	rabs := r
+	if rabs < 0 {
+		rabs = -rabs
+	}
+
+	var half bool
+	if y > 0 {
+		half = rabs >= y-rabs
+	} else {
+		half = rabs >= -(y + rabs)
+	}
+
+	switch {
+	case !half:
+		return q
+	case (r < 0) != (y < 0):
+		return q - 1
+	default:
+		return q + 1
+	}
+}
+
+// DivEuclid returns the Euclidean quotient of x and y, which is the q for which
+// x == q*y + r with 0 <= r < |y|, e.g. DivEuclid(-7, 2) is -4 and
+// DivEuclid(-7, -2) is 4.
+func DivEuclid[T constraints.Integer](x T, y T) T {
+	q, r := x/y, x%y
+	if r < 0 {
+		if y > 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+// ModEuclid returns the Euclidean remainder of x and y, which is always in
+// [0, |y|), e.g. ModEuclid(-7, 2) and ModEuclid(-7, -2) are both 1.
+func ModEuclid[T constraints.Integer](x T, y T) T {
+	r := x % y
+	if r < 0 {
+		if y > 0 {
+			r += y
+		} else {
+			r -= y
+		}
+	}
+	return r
+}
+
+// DivMod returns the floored quotient and remainder of x and y, such that
+// x == q*y + r and r has the same sign as y, e.g. DivMod(-7, 2) is (-4, 1) and
+// DivMod(7, -2) is (-4, -1). The quotient is the same as DivFloor(x, y).
+func DivMod[T constraints.Integer](x T, y T) (q T, r T) {
+	q, r = x/y, x%y
+	if r != 0 && (r < 0) != (y < 0) {
+		q--
+		r += y
+	}
+	return q, r
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestDiv(t *testing.T) {
+	cases := []struct {
+		x, y                       int
+		floor, ceil, round, euclid int
+		modEuclid, mod             int
+	}{
+		{7, 2, 3, 4, 4, 3, 1, 1},
+		{-7, 2, -4, -3, -4, -4, 1, 1},
+		{7, -2, -4, -3, -4, -3, 1, -1},
+		{-7, -2, 3, 4, 4, 4, 1, -1},
+		{8, 3, 2, 3, 3, 2, 2, 2},
+		{-8, 3, -3, -2, -3, -3, 1, 1},
+		{8, -3, -3, -2, -3, -2, 2, -1},
+		{-8, -3, 2, 3, 3, 3, 1, -2},
+		{7, 3, 2, 3, 2, 2, 1, 1},
+		{-7, 3, -3, -2, -2, -3, 2, 2},
+		{6, 2, 3, 3, 3, 3, 0, 0},
+		{-6, 2, -3, -3, -3, -3, 0, 0},
+		{6, -2, -3, -3, -3, -3, 0, 0},
+		{-6, -2, 3, 3, 3, 3, 0, 0},
+		{0, 5, 0, 0, 0, 0, 0, 0},
+		{0, -5, 0, 0, 0, 0, 0, 0},
+		{1, 5, 0, 1, 0, 0, 1, 1},
+		{-1, 5, -1, 0, 0, -1, 4, 4},
+	}
+
+	for _, tt := range cases {
+		msg := []any{"%d, %d", tt.x, tt.y}
+		require.Equal(t, tt.floor, math.DivFloor(tt.x, tt.y), msg...)
+		require.Equal(t, tt.ceil, math.DivCeil(tt.x, tt.y), msg...)
+		require.Equal(t, tt.round, math.DivRound(tt.x, tt.y), msg...)
+		require.Equal(t, tt.euclid, math.DivEuclid(tt.x, tt.y), msg...)
+		require.Equal(t, tt.modEuclid, math.ModEuclid(tt.x, tt.y), msg...)
+
+		q, r := math.DivMod(tt.x, tt.y)
+		require.Equal(t, tt.floor, q, msg...)
+		require.Equal(t, tt.mod, r, msg...)
+		require.Equal(t, tt.x, q*tt.y+r, msg...)
+	}
+}
+
+func TestDivMinByMinusOne(t *testing.T) {
+	var (
+		minVal   = int64(stdmath.MinInt64)
+		minusOne = int64(-1)
+	)
+
+	// Like Go's / and %, the quotient wraps and the remainder is zero.
+	require.Equal(t, minVal, math.DivFloor(minVal, minusOne))
+	require.Equal(t, minVal, math.DivCeil(minVal, minusOne))
+	require.Equal(t, minVal, math.DivRound(minVal, minusOne))
+	require.Equal(t, minVal, math.DivEuclid(minVal, minusOne))
+	require.Equal(t, int64(0), math.ModEuclid(minVal, minusOne))
+
+	q, r := math.DivMod(minVal, minusOne)
+	require.Equal(t, minVal, q)
+	require.Equal(t, int64(0), r)
+
+	// Other extreme divisors do not overflow.
+	require.Equal(t, int64(1), math.DivRound(minVal, minVal))
+	require.Equal(t, int64(0), math.DivRound(1, minVal))
+	require.Equal(t, int64(-1), math.DivRound(minVal+1, stdmath.MaxInt64))
+	require.Equal(t, int64(1), math.DivRound(minVal/2, minVal))
+	require.Equal(t, int64(0), math.DivRound(minVal/2+1, minVal))
+	require.Equal(t, int64(stdmath.MaxInt64), math.ModEuclid(-1, minVal))
+	require.Equal(t, uint64(1), math.DivRound[uint64](stdmath.MaxUint64/2+1, stdmath.MaxUint64))
+	require.Equal(t, uint64(0), math.DivRound[uint64](stdmath.MaxUint64/2, stdmath.MaxUint64))
+}
+
+func TestDivByZero(t *testing.T) {
+	var zero int
+
+	require.Panics(t, func() { math.DivFloor(1, zero) })
+	require.Panics(t, func() { math.DivCeil(1, zero) })
+	require.Panics(t, func() { math.DivRound(1, zero) })
+	require.Panics(t, func() { math.DivEuclid(1, zero) })
+	require.Panics(t, func() { math.ModEuclid(1, zero) })
+	require.Panics(t, func() { math.DivMod(1, zero) })
+	require.Panics(t, func() { math.DivFloor(uint8(1), uint8(zero)) })
+}
+
+func TestDivExhaustive(t *testing.T) {
+	testDivExhaustive[int8](t)
+	testDivExhaustive[uint8](t)
+}
+
+// testDivExhaustive compares each division helper against an oracle computed
+// with int64 arithmetic for every x and non-zero y.
+//
+//nolint:gocyclo
+func testDivExhaustive[T int8 | uint8](t *testing.T) {
+	var (
+		minVal = int64(math.MinValue[T]())
+		maxVal = int64(math.MaxValue[T]())
+	)
+
+	for x := minVal; x <= maxVal; x++ {
+		for y := minVal; y <= maxVal; y++ {
+			if y == 0 {
+				continue
+			}
+
+			var (
+				floor  = floorDiv(x, y)
+				ceil   = -floorDiv(-x, y)
+				euclid = floor
+				mod    = x - floor*y
+				round  = floor
+			)
+
+			if mod != 0 && y < 0 {
+				euclid++
+			}
+
+			// The fractional part of x/y is |mod|/|y|, so round half away from
+			// zero by comparing twice that distance against |y|.
+			if d, yabs := abs64(2*mod), abs64(y); d > yabs || (d == yabs && floor >= 0) {
+				round = ceil
+			}
+
+			var (
+				xt = T(x)
+				yt = T(y)
+				m  = fmtDiv(x, y)
+			)
+
+			if T(floor) != math.DivFloor(xt, yt) ||
+				T(ceil) != math.DivCeil(xt, yt) ||
+				T(round) != math.DivRound(xt, yt) ||
+				T(euclid) != math.DivEuclid(xt, yt) ||
+				T(x-euclid*y) != math.ModEuclid(xt, yt) {
+				require.Equal(t, T(floor), math.DivFloor(xt, yt), m)
+				require.Equal(t, T(ceil), math.DivCeil(xt, yt), m)
+				require.Equal(t, T(round), math.DivRound(xt, yt), m)
+				require.Equal(t, T(euclid), math.DivEuclid(xt, yt), m)
+				require.Equal(t, T(x-euclid*y), math.ModEuclid(xt, yt), m)
+			}
+
+			if q, r := math.DivMod(xt, yt); q != T(floor) || r != T(mod) {
+				require.Equal(t, T(floor), q, m)
+				require.Equal(t, T(mod), r, m)
+			}
+		}
+	}
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func fmtDiv(x, y int64) string {
+	return strconv.FormatInt(x, 10) + " / " + strconv.FormatInt(y, 10)
+}
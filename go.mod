@@ -1,6 +1,6 @@
 module go.mway.dev/math
 
-go 1.18
+go 1.22
 
 require (
 	github.com/stretchr/testify v1.7.1
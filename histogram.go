@@ -0,0 +1,335 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Histogram is a fixed-memory histogram of non-negative integers in the style
+// of HdrHistogram. Values are counted in buckets whose width is proportional to
+// their magnitude, such that every recorded value is represented to within the
+// configured number of significant decimal digits, and the memory used depends
+// only on the trackable range and precision (not on the number of values).
+//
+// Histogram is not safe for concurrent use; each goroutine should use its own
+// Histogram, combining them with Merge.
+type Histogram[T constraints.Integer] struct {
+	lowest       int64
+	highest      int64
+	digits       int
+	unitMag      int   // log2 of the smallest distinguishable unit
+	subHalfMag   int   // log2 of subHalfCount
+	subCount     int   // number of sub-buckets per bucket
+	subHalfCount int   // number of sub-buckets in the upper half of a bucket
+	subMask      int64 // mask of the bits covered by bucket 0
+	counts       []int64
+	total        int64
+	min          T
+	max          T
+}
+
+// HistogramBucket is a range of equivalent values in a Histogram, and the
+// number of values recorded within it.
+type HistogramBucket[T constraints.Integer] struct {
+	// Low is the lowest value in the bucket (inclusive).
+	Low T
+	// High is the highest value in the bucket (inclusive).
+	High T
+	// Count is the number of values recorded in the bucket.
+	Count int64
+}
+
+// NewHistogram returns a new Histogram able to track values in [0,highest],
+// with a resolution of at least lowest and a precision of the given number of
+// significant decimal digits. lowest must be at least 1, highest must be at
+// least twice lowest, and digits must be in [1,5]; otherwise, NewHistogram
+// panics.
+func NewHistogram[T constraints.Integer](lowest T, highest T, digits int) *Histogram[T] {
+	lo, lerr := Convert[int64](lowest)
+	hi, herr := Convert[int64](highest)
+
+	switch {
+	case lerr != nil || lo < 1:
+		panic(fmt.Sprintf("math: invalid histogram lowest value %v", lowest))
+	case herr != nil || hi < 2*lo:
+		panic(fmt.Sprintf("math: invalid histogram highest value %v", highest))
+	case digits < 1 || digits > 5:
+		panic(fmt.Sprintf("math: invalid histogram precision %d", digits))
+	}
+
+	var (
+		subCount   = NextPowerOf2(2 * int64(math.Pow10(digits)))
+		subHalfMag = bits.TrailingZeros64(uint64(subCount)) - 1
+		unitMag    = bits.Len64(uint64(lo)) - 1
+	)
+
+	if unitMag+subHalfMag > 61 {
+		panic(fmt.Sprintf(
+			"math: histogram precision %d is too high for lowest value %v",
+			digits,
+			lowest,
+		))
+	}
+
+	// Each bucket beyond the first doubles the trackable range.
+	var (
+		buckets     = 1
+		untrackable = subCount << unitMag
+	)
+
+	for untrackable <= hi {
+		buckets++
+		if untrackable > math.MaxInt64/2 {
+			break
+		}
+		untrackable <<= 1
+	}
+
+	return &Histogram[T]{
+		lowest:       lo,
+		highest:      hi,
+		digits:       digits,
+		unitMag:      unitMag,
+		subHalfMag:   subHalfMag,
+		subCount:     int(subCount),
+		subHalfCount: int(subCount / 2),
+		subMask:      (subCount - 1) << unitMag,
+		counts:       make([]int64, (buckets+1)*int(subCount/2)),
+	}
+}
+
+// Record records x, reporting whether it was within the trackable range of the
+// histogram.
+func (h *Histogram[T]) Record(x T) bool {
+	return h.RecordN(x, 1)
+}
+
+// RecordN records n occurrences of x, reporting whether x was within the
+// trackable range of the histogram. Non-positive values of n are ignored.
+func (h *Histogram[T]) RecordN(x T, n int64) bool {
+	if x < 0 || uint64(x) > uint64(h.highest) {
+		return false
+	}
+
+	if n <= 0 {
+		return true
+	}
+
+	h.counts[h.index(int64(x))] += n
+
+	if h.total == 0 {
+		h.min, h.max = x, x
+	} else {
+		h.min = Min(h.min, x)
+		h.max = Max(h.max, x)
+	}
+
+	h.total += n
+	return true
+}
+
+// Merge records all values counted by other into h, returning the number of
+// values that were dropped because they were outside of the trackable range of
+// h. If h and other were created with different parameters, each of other's
+// buckets is recorded as its lowest value.
+func (h *Histogram[T]) Merge(other *Histogram[T]) int64 {
+	if other.total == 0 {
+		return 0
+	}
+
+	if h.sameLayout(other) {
+		for i, count := range other.counts {
+			h.counts[i] += count
+		}
+
+		if h.total == 0 {
+			h.min, h.max = other.min, other.max
+		} else {
+			h.min = Min(h.min, other.min)
+			h.max = Max(h.max, other.max)
+		}
+
+		h.total += other.total
+		return 0
+	}
+
+	var dropped int64
+	other.Range(func(b HistogramBucket[T]) bool {
+		if !h.RecordN(b.Low, b.Count) {
+			dropped += b.Count
+		}
+		return true
+	})
+
+	return dropped
+}
+
+// Reset clears all recorded values.
+func (h *Histogram[T]) Reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+
+	h.total = 0
+	h.min = 0
+	h.max = 0
+}
+
+// Count returns the number of values that have been recorded.
+func (h *Histogram[T]) Count() int64 {
+	return h.total
+}
+
+// Min returns the minimum value that has been recorded, or 0 if no values have
+// been recorded.
+func (h *Histogram[T]) Min() T {
+	return h.min
+}
+
+// Max returns the maximum value that has been recorded, or 0 if no values have
+// been recorded.
+func (h *Histogram[T]) Max() T {
+	return h.max
+}
+
+// Mean returns an approximation of the average of all recorded values, using
+// the midpoint of each bucket, or NaN if no values have been recorded.
+func (h *Histogram[T]) Mean() float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+
+	var total float64
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+
+		var (
+			lo   = h.valueAt(i)
+			size = h.bucketSize(lo)
+		)
+
+		total += float64(count) * float64(lo+size/2)
+	}
+
+	return total / float64(h.total)
+}
+
+// Quantile returns the q-th quantile of all recorded values, where q is
+// clamped to [0,1], to within the precision of the histogram. The result is
+// the highest value equivalent to the bucket containing the quantile, or 0 if
+// no values have been recorded.
+func (h *Histogram[T]) Quantile(q float64) T {
+	switch {
+	case h.total == 0:
+		return 0
+	case !(q > 0):
+		return h.min
+	}
+
+	var (
+		target = Max(int64(math.Ceil(Min(q, 1)*float64(h.total))), 1)
+		total  int64
+	)
+
+	for i, count := range h.counts {
+		total += count
+		if total >= target {
+			lo := h.valueAt(i)
+			return T(Min(lo+h.bucketSize(lo)-1, int64(h.max)))
+		}
+	}
+
+	return h.max
+}
+
+// Range calls fn for each non-empty bucket in the histogram in ascending
+// order, stopping if fn returns false.
+func (h *Histogram[T]) Range(fn func(HistogramBucket[T]) bool) {
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+
+		lo := h.valueAt(i)
+		if !fn(HistogramBucket[T]{
+			Low:   T(lo),
+			High:  ConvertSat[T](lo + h.bucketSize(lo) - 1),
+			Count: count,
+		}) {
+			return
+		}
+	}
+}
+
+// index returns the index of the count for v.
+func (h *Histogram[T]) index(v int64) int {
+	var (
+		pow2Ceil = 64 - bits.LeadingZeros64(uint64(v|h.subMask))
+		bucket   = pow2Ceil - h.unitMag - (h.subHalfMag + 1)
+		sub      = int(v >> (bucket + h.unitMag))
+	)
+
+	return (bucket+1)<<h.subHalfMag + (sub - h.subHalfCount)
+}
+
+// valueAt returns the lowest value that is counted at index i.
+func (h *Histogram[T]) valueAt(i int) int64 {
+	var (
+		bucket = i>>h.subHalfMag - 1
+		sub    = i&(h.subHalfCount-1) + h.subHalfCount
+	)
+
+	if bucket < 0 {
+		sub -= h.subHalfCount
+		bucket = 0
+	}
+
+	return int64(sub) << (bucket + h.unitMag)
+}
+
+// bucketSize returns the number of values that are equivalent to v.
+func (h *Histogram[T]) bucketSize(v int64) int64 {
+	var (
+		pow2Ceil = 64 - bits.LeadingZeros64(uint64(v|h.subMask))
+		bucket   = pow2Ceil - h.unitMag - (h.subHalfMag + 1)
+		sub      = int(v >> (bucket + h.unitMag))
+	)
+
+	if sub >= h.subCount {
+		bucket++
+	}
+
+	return 1 << (h.unitMag + bucket)
+}
+
+func (h *Histogram[T]) sameLayout(other *Histogram[T]) bool {
+	return h.unitMag == other.unitMag &&
+		h.subHalfMag == other.subHalfMag &&
+		h.highest == other.highest &&
+		len(h.counts) == len(other.counts)
+}
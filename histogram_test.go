@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestNewHistogramPanics(t *testing.T) {
+	require.Panics(t, func() { math.NewHistogram(0, 100, 3) })
+	require.Panics(t, func() { math.NewHistogram(-1, 100, 3) })
+	require.Panics(t, func() { math.NewHistogram(10, 19, 3) })
+	require.Panics(t, func() { math.NewHistogram[uint64](1, stdmath.MaxUint64, 3) })
+	require.Panics(t, func() { math.NewHistogram(1, 100, 0) })
+	require.Panics(t, func() { math.NewHistogram(1, 100, 6) })
+	require.Panics(t, func() { math.NewHistogram[int64](1<<60, stdmath.MaxInt64, 5) })
+	require.NotPanics(t, func() { math.NewHistogram[int64](1, stdmath.MaxInt64, 5) })
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	h := math.NewHistogram(1, 1000, 2)
+
+	require.Equal(t, int64(0), h.Count())
+	require.Equal(t, 0, h.Min())
+	require.Equal(t, 0, h.Max())
+	require.Equal(t, 0, h.Quantile(0.5))
+	require.True(t, stdmath.IsNaN(h.Mean()))
+
+	h.Range(func(math.HistogramBucket[int]) bool {
+		require.FailNow(t, "unexpected bucket")
+		return false
+	})
+}
+
+func TestHistogramPrecision(t *testing.T) {
+	for digits := 1; digits <= 5; digits++ {
+		var (
+			h         = math.NewHistogram[int64](1, 1<<40, digits)
+			tolerance = stdmath.Pow10(-digits)
+		)
+
+		for v := int64(0); v < 1<<40; v = v*3/2 + 1 {
+			require.True(t, h.Record(v))
+		}
+
+		h.Range(func(b math.HistogramBucket[int64]) bool {
+			require.LessOrEqual(t, b.Low, b.High)
+			require.LessOrEqual(t, float64(b.High-b.Low), tolerance*float64(b.Low), b)
+			return true
+		})
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := math.NewHistogram(1, 1_000_000, 3)
+
+	for i := 1; i <= 10000; i++ {
+		require.True(t, h.Record(i))
+	}
+
+	require.Equal(t, int64(10000), h.Count())
+	require.Equal(t, 1, h.Min())
+	require.Equal(t, 10000, h.Max())
+	require.Equal(t, 1, h.Quantile(0))
+	require.Equal(t, 10000, h.Quantile(1))
+	require.InDelta(t, 5000.5, h.Mean(), 5000.5*0.001)
+
+	for _, q := range []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+		want := q * 10000
+		require.InDelta(t, want, h.Quantile(q), want*0.001, "q=%v", q)
+	}
+}
+
+func TestHistogramDurations(t *testing.T) {
+	h := math.NewHistogram(time.Microsecond, time.Hour, 3)
+
+	for i := 1; i <= 1000; i++ {
+		require.True(t, h.Record(time.Duration(i)*100*time.Microsecond))
+	}
+
+	require.Equal(t, 100*time.Microsecond, h.Min())
+	require.Equal(t, 100*time.Millisecond, h.Max())
+	require.InDelta(t, 50*time.Millisecond, h.Quantile(0.5), float64(50*time.Microsecond))
+	require.InDelta(t, 99*time.Millisecond, h.Quantile(0.99), float64(99*time.Microsecond))
+
+	// Values below the lowest discernible value are still recorded.
+	require.True(t, h.Record(time.Nanosecond))
+	require.Equal(t, time.Nanosecond, h.Min())
+}
+
+func TestHistogramRange(t *testing.T) {
+	h := math.NewHistogram[uint8](1, 255, 1)
+
+	require.True(t, h.Record(0))
+	require.True(t, h.RecordN(200, 3))
+	require.True(t, h.RecordN(5, 2))
+	require.True(t, h.RecordN(7, 0))
+	require.True(t, h.Record(255))
+
+	var buckets []math.HistogramBucket[uint8]
+	h.Range(func(b math.HistogramBucket[uint8]) bool {
+		buckets = append(buckets, b)
+		return true
+	})
+
+	require.Len(t, buckets, 4)
+	require.Equal(t, uint8(0), buckets[0].Low)
+	require.Equal(t, int64(1), buckets[0].Count)
+	require.Equal(t, int64(2), buckets[1].Count)
+	require.Equal(t, int64(3), buckets[2].Count)
+	require.Equal(t, uint8(255), buckets[3].High)
+	require.Equal(t, int64(7), h.Count())
+	require.Equal(t, uint8(255), h.Quantile(1))
+
+	var seen int
+	h.Range(func(math.HistogramBucket[uint8]) bool {
+		seen++
+		return false
+	})
+	require.Equal(t, 1, seen)
+}
+
+func TestHistogramOutOfRange(t *testing.T) {
+	h := math.NewHistogram[int8](1, 100, 2)
+
+	require.False(t, h.Record(-1))
+	require.False(t, h.Record(101))
+	require.True(t, h.Record(100))
+	require.Equal(t, int64(1), h.Count())
+	require.Equal(t, int8(100), h.Quantile(0.5))
+}
+
+func TestHistogramMerge(t *testing.T) {
+	var (
+		a     = math.NewHistogram(1, 100000, 3)
+		b     = math.NewHistogram(1, 100000, 3)
+		c     = math.NewHistogram(1, 1000, 3)
+		whole = math.NewHistogram(1, 100000, 3)
+	)
+
+	for i := 1; i <= 2000; i++ {
+		whole.Record(i)
+		if i%2 == 0 {
+			a.Record(i)
+		} else {
+			b.Record(i)
+		}
+	}
+
+	require.Equal(t, int64(0), a.Merge(b))
+	require.Equal(t, whole.Count(), a.Count())
+	require.Equal(t, whole.Min(), a.Min())
+	require.Equal(t, whole.Max(), a.Max())
+
+	for q := 0.0; q <= 1; q += 0.01 {
+		require.Equal(t, whole.Quantile(q), a.Quantile(q))
+	}
+
+	require.Equal(t, int64(1000), c.Merge(a))
+	require.Equal(t, int64(1000), c.Count())
+	require.Equal(t, int64(0), c.Merge(math.NewHistogram(1, 10, 1)))
+
+	a.Reset()
+	require.Equal(t, int64(0), a.Count())
+	require.Equal(t, 0, a.Quantile(0.5))
+	require.Equal(t, int64(0), a.Merge(c))
+	require.Equal(t, int64(1000), a.Count())
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+	"unsafe"
+)
+
+// MinValue returns the minimum value representable by T. For floating point
+// types, this is the negative of the largest finite value.
+func MinValue[T Numeric]() T {
+	switch {
+	case IsFloat[T]():
+		return -MaxValue[T]()
+	case IsSigned[T]():
+		return -MaxValue[T]() - 1
+	default:
+		return 0
+	}
+}
+
+// MaxValue returns the maximum value representable by T. For floating point
+// types, this is the largest finite value.
+func MaxValue[T Numeric]() T {
+	size := BitSize[T]()
+
+	switch {
+	case IsFloat[T]() && size == 32:
+		maxFloat := math.MaxFloat32
+		return T(maxFloat)
+	case IsFloat[T]():
+		maxFloat := math.MaxFloat64
+		return T(maxFloat)
+	case IsSigned[T]():
+		return T(^uint64(0) >> (65 - size))
+	default:
+		return T(^uint64(0) >> (64 - size))
+	}
+}
+
+// BitSize returns the size of T in bits.
+func BitSize[T Numeric]() int {
+	var zero T
+	return int(unsafe.Sizeof(zero)) * 8
+}
+
+// IsSigned reports whether T is able to represent negative values, which is
+// true of signed integer and floating point types.
+func IsSigned[T Numeric]() bool {
+	var zero T
+	return zero-1 < zero
+}
+
+// IsFloat reports whether T is a floating point type.
+func IsFloat[T Numeric]() bool {
+	var one T = 1
+	return one/2 != 0
+}
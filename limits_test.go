@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+type namedUint8 uint8
+
+func TestMinValue(t *testing.T) {
+	require.Equal(t, int(stdmath.MinInt), math.MinValue[int]())
+	require.Equal(t, int8(stdmath.MinInt8), math.MinValue[int8]())
+	require.Equal(t, int16(stdmath.MinInt16), math.MinValue[int16]())
+	require.Equal(t, int32(stdmath.MinInt32), math.MinValue[int32]())
+	require.Equal(t, int64(stdmath.MinInt64), math.MinValue[int64]())
+	require.Equal(t, uint(0), math.MinValue[uint]())
+	require.Equal(t, uint8(0), math.MinValue[uint8]())
+	require.Equal(t, uint16(0), math.MinValue[uint16]())
+	require.Equal(t, uint32(0), math.MinValue[uint32]())
+	require.Equal(t, uint64(0), math.MinValue[uint64]())
+	require.Equal(t, uintptr(0), math.MinValue[uintptr]())
+	require.Equal(t, time.Duration(stdmath.MinInt64), math.MinValue[time.Duration]())
+	require.Equal(t, namedUint8(0), math.MinValue[namedUint8]())
+	require.Equal(t, float32(-stdmath.MaxFloat32), math.MinValue[float32]())
+	require.Equal(t, float64(-stdmath.MaxFloat64), math.MinValue[float64]())
+}
+
+func TestMaxValue(t *testing.T) {
+	require.Equal(t, int(stdmath.MaxInt), math.MaxValue[int]())
+	require.Equal(t, int8(stdmath.MaxInt8), math.MaxValue[int8]())
+	require.Equal(t, int16(stdmath.MaxInt16), math.MaxValue[int16]())
+	require.Equal(t, int32(stdmath.MaxInt32), math.MaxValue[int32]())
+	require.Equal(t, int64(stdmath.MaxInt64), math.MaxValue[int64]())
+	require.Equal(t, uint(stdmath.MaxUint), math.MaxValue[uint]())
+	require.Equal(t, uint8(stdmath.MaxUint8), math.MaxValue[uint8]())
+	require.Equal(t, uint16(stdmath.MaxUint16), math.MaxValue[uint16]())
+	require.Equal(t, uint32(stdmath.MaxUint32), math.MaxValue[uint32]())
+	require.Equal(t, uint64(stdmath.MaxUint64), math.MaxValue[uint64]())
+	require.Equal(t, ^uintptr(0), math.MaxValue[uintptr]())
+	require.Equal(t, time.Duration(stdmath.MaxInt64), math.MaxValue[time.Duration]())
+	require.Equal(t, namedUint8(stdmath.MaxUint8), math.MaxValue[namedUint8]())
+	require.Equal(t, float32(stdmath.MaxFloat32), math.MaxValue[float32]())
+	require.Equal(t, float64(stdmath.MaxFloat64), math.MaxValue[float64]())
+}
+
+func TestBitSize(t *testing.T) {
+	require.Equal(t, strconv.IntSize, math.BitSize[int]())
+	require.Equal(t, 8, math.BitSize[int8]())
+	require.Equal(t, 16, math.BitSize[int16]())
+	require.Equal(t, 32, math.BitSize[int32]())
+	require.Equal(t, 64, math.BitSize[int64]())
+	require.Equal(t, strconv.IntSize, math.BitSize[uint]())
+	require.Equal(t, 8, math.BitSize[uint8]())
+	require.Equal(t, 16, math.BitSize[uint16]())
+	require.Equal(t, 32, math.BitSize[uint32]())
+	require.Equal(t, 64, math.BitSize[uint64]())
+	require.Equal(t, 64, math.BitSize[time.Duration]())
+	require.Equal(t, 8, math.BitSize[namedUint8]())
+	require.Equal(t, 32, math.BitSize[float32]())
+	require.Equal(t, 64, math.BitSize[float64]())
+}
+
+func TestIsSigned(t *testing.T) {
+	require.True(t, math.IsSigned[int]())
+	require.True(t, math.IsSigned[int8]())
+	require.True(t, math.IsSigned[int16]())
+	require.True(t, math.IsSigned[int32]())
+	require.True(t, math.IsSigned[int64]())
+	require.False(t, math.IsSigned[uint]())
+	require.False(t, math.IsSigned[uint8]())
+	require.False(t, math.IsSigned[uint16]())
+	require.False(t, math.IsSigned[uint32]())
+	require.False(t, math.IsSigned[uint64]())
+	require.False(t, math.IsSigned[uintptr]())
+	require.True(t, math.IsSigned[time.Duration]())
+	require.False(t, math.IsSigned[namedUint8]())
+	require.True(t, math.IsSigned[float32]())
+	require.True(t, math.IsSigned[float64]())
+}
+
+func TestIsFloat(t *testing.T) {
+	require.False(t, math.IsFloat[int]())
+	require.False(t, math.IsFloat[int8]())
+	require.False(t, math.IsFloat[int16]())
+	require.False(t, math.IsFloat[int32]())
+	require.False(t, math.IsFloat[int64]())
+	require.False(t, math.IsFloat[uint]())
+	require.False(t, math.IsFloat[uint8]())
+	require.False(t, math.IsFloat[uint16]())
+	require.False(t, math.IsFloat[uint32]())
+	require.False(t, math.IsFloat[uint64]())
+	require.False(t, math.IsFloat[uintptr]())
+	require.False(t, math.IsFloat[time.Duration]())
+	require.False(t, math.IsFloat[namedUint8]())
+	require.True(t, math.IsFloat[float32]())
+	require.True(t, math.IsFloat[float64]())
+}
@@ -23,7 +23,9 @@ package math
 
 import (
 	"math"
-	_ "unsafe" // for go:linkname
+	"math/bits"
+	"math/rand/v2"
+	"strconv"
 
 	"golang.org/x/exp/constraints"
 )
@@ -45,7 +47,9 @@ type Unsigned32 interface {
 	~int | ~int64 | ~uint | ~uint32 | ~uint64 | constraints.Float
 }
 
-// Abs returns the absolute value of the given signed number.
+// Abs returns the absolute value of the given signed number. The absolute
+// value of the minimum value of a signed integer type is not representable,
+// and so it is returned unchanged; AbsSat saturates instead.
 func Abs[T SignedNumeric](x T) T {
 	if x < 0 {
 		return -x
@@ -158,42 +162,72 @@ func MaxN[T Numeric](x ...T) T {
 	}
 }
 
-// Mean returns the truncated average value of all given numbers.
+// Mean returns the truncated average value of all given numbers. The sum is
+// accumulated in T, and so may wrap for integer types; MeanWide should be used
+// when the sum of x is not known to fit in T. For floating point types,
+// MeanPrecise avoids the rounding error of naive summation.
 func Mean[T Numeric](x ...T) T {
-	var (
-		size  = len(x)
-		total T
-	)
+	return Sum(x...) / T(len(x))
+}
 
-	for len(x) >= 8 {
-		total += x[0] + x[1] + x[2] + x[3] + x[4] + x[5] + x[6] + x[7]
-		x = x[8:]
+// MeanFloat64 returns the average value of all given numbers. Like Mean, the
+// sum is accumulated in T and may wrap for integer types; MeanWideFloat64
+// should be used when the sum of x is not known to fit in T, and MeanPrecise
+// when precision matters for floating point types.
+func MeanFloat64[T Numeric](x ...T) float64 {
+	return float64(Sum(x...)) / float64(len(x))
+}
+
+// MeanWide returns the truncated average value of all given numbers. Unlike
+// Mean, the sum is accumulated in a widened type so that it cannot wrap:
+// integers are summed with a 128-bit accumulator, which is exact for every
+// integer type, and floats are summed as float64. The returned bool is false
+// if no numbers are given, or if a floating point sum overflows to infinity
+// without x containing an infinity.
+func MeanWide[T Numeric](x ...T) (T, bool) {
+	if len(x) == 0 {
+		return 0, false
 	}
 
-	for _, n := range x {
-		total += n
+	if IsFloat[T]() {
+		total, ok := sumFloat64(x)
+		return T(total / float64(len(x))), ok
+	}
+
+	hi, lo, neg := sum128(x)
+	q, _ := bits.Div64(hi, lo, uint64(len(x)))
+	if neg {
+		return T(-int64(q)), true
 	}
 
-	return total / T(size)
+	return T(q), true
 }
 
-// MeanFloat64 returns the average value of all given numbers.
-func MeanFloat64[T Numeric](x ...T) float64 {
-	var (
-		size  = len(x)
-		total T
-	)
+// MeanWideFloat64 returns the average value of all given numbers, accumulating
+// the sum in a widened type as described by MeanWide. The returned bool is
+// false under the same conditions as MeanWide.
+func MeanWideFloat64[T Numeric](x ...T) (float64, bool) {
+	if len(x) == 0 {
+		return 0, false
+	}
 
-	for len(x) >= 8 {
-		total += x[0] + x[1] + x[2] + x[3] + x[4] + x[5] + x[6] + x[7]
-		x = x[8:]
+	if IsFloat[T]() {
+		total, ok := sumFloat64(x)
+		return total / float64(len(x)), ok
 	}
 
-	for _, n := range x {
-		total += n
+	var (
+		size        = uint64(len(x))
+		hi, lo, neg = sum128(x)
+		q, r        = bits.Div64(hi, lo, size)
+		mean        = float64(q) + float64(r)/float64(size)
+	)
+
+	if neg {
+		return -mean, true
 	}
 
-	return float64(total) / float64(size)
+	return mean, true
 }
 
 // Clamp clamps the given value between [min,max] (inclusive).
@@ -230,42 +264,169 @@ func ClampMax[T Numeric](x T, max T) T {
 }
 
 // NextPowerOf2 returns the next T greater than x that is a power of 2. If x is
-// a power of 2 itself, x is returned.
+// a power of 2 itself, x is returned; if x is less than 1, 1 is returned. If
+// the next power of 2 is not representable by T, or if x is NaN or infinite,
+// 0 is returned.
 func NextPowerOf2[T Numeric](x T) T {
 	if x <= 1 {
 		return 1
 	}
 
-	var (
-		l2 = math.Log2(float64(x))
-		hi = T(int(1) << int(math.Ceil(l2)))
-	)
+	if IsFloat[T]() {
+		return nextPowerOf2Float[T](float64(x))
+	}
+
+	shift := bits.Len64(uint64(x) - 1)
+	if shift > maxPowerOf2Shift[T]() {
+		return 0
+	}
 
-	return hi
+	return T(uint64(1) << shift)
 }
 
-// ClosestPowerOf2 returns the next T greater than x that is a power of 2.
+// ClosestPowerOf2 returns the power of 2 that is closest to x, preferring the
+// greater power of 2 if x is equidistant from two. If x is less than 1, 1 is
+// returned. If the greater power of 2 is not representable by T, the lesser is
+// returned; if x is NaN or infinite, 0 is returned.
 func ClosestPowerOf2[T Numeric](x T) T {
 	if x <= 1 {
 		return 1
 	}
 
+	if IsFloat[T]() {
+		return closestPowerOf2Float[T](float64(x))
+	}
+
 	var (
-		l2 = math.Log2(float64(x))
-		lo = T(int(1) << int(math.Floor(l2)))
-		hi = T(int(1) << int(math.Ceil(l2)))
+		u     = uint64(x)
+		shift = bits.Len64(u) - 1
+		lo    = uint64(1) << shift
+		hi    = lo << 1
 	)
 
-	if hi-x > x-lo {
-		return lo
+	if u == lo || shift+1 > maxPowerOf2Shift[T]() || hi-u > u-lo {
+		return T(lo)
+	}
+
+	return T(hi)
+}
+
+func nextPowerOf2Float[T Numeric](f float64) T {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+
+	// f = frac * 2^exp, where frac is in [0.5,1); f is only a power of 2 if
+	// frac is exactly 0.5.
+	if frac, exp := math.Frexp(f); frac != 0.5 {
+		f = math.Ldexp(1, exp)
+	}
+
+	if f > float64(MaxValue[T]()) {
+		return 0
+	}
+
+	return T(f)
+}
+
+func closestPowerOf2Float[T Numeric](f float64) T {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+
+	frac, exp := math.Frexp(f)
+	if frac == 0.5 {
+		return T(f)
+	}
+
+	var (
+		lo = math.Ldexp(1, exp-1)
+		hi = math.Ldexp(1, exp)
+	)
+
+	if hi > float64(MaxValue[T]()) || hi-f > f-lo {
+		return T(lo)
+	}
+
+	return T(hi)
+}
+
+// IsPowerOf2 reports whether x is a power of 2.
+func IsPowerOf2[T constraints.Integer](x T) bool {
+	return x > 0 && x&(x-1) == 0
+}
+
+// PrevPowerOf2 returns the previous T less than x that is a power of 2. If x is
+// a power of 2 itself, x is returned; if x is less than 1, 0 is returned.
+func PrevPowerOf2[T constraints.Integer](x T) T {
+	if x < 1 {
+		return 0
+	}
+
+	return T(uint64(1) << (bits.Len64(uint64(x)) - 1))
+}
+
+// Log2Floor returns the base-2 logarithm of x, rounded down. If x is less than
+// 1, -1 is returned.
+func Log2Floor[T constraints.Integer](x T) int {
+	if x < 1 {
+		return -1
+	}
+
+	return bits.Len64(uint64(x)) - 1
+}
+
+// Log2Ceil returns the base-2 logarithm of x, rounded up. If x is less than 1,
+// -1 is returned.
+func Log2Ceil[T constraints.Integer](x T) int {
+	if x < 1 {
+		return -1
+	}
+
+	return bits.Len64(uint64(x) - 1)
+}
+
+// AlignUp rounds x up to the nearest multiple of align, which must be a power
+// of 2. The returned bool is false if align is not a power of 2, or if the
+// result is not representable by T.
+func AlignUp[T constraints.Integer](x T, align T) (T, bool) {
+	if !IsPowerOf2(align) {
+		return 0, false
+	}
+
+	y, ok := AddChecked(x, align-1)
+	if !ok {
+		return 0, false
+	}
+
+	return y &^ (align - 1), true
+}
+
+// AlignDown rounds x down to the nearest multiple of align, which must be a
+// power of 2. The returned bool is false if align is not a power of 2; the
+// result is otherwise always representable by T.
+func AlignDown[T constraints.Integer](x T, align T) (T, bool) {
+	if !IsPowerOf2(align) {
+		return 0, false
 	}
 
-	return hi
+	return x &^ (align - 1), true
+}
+
+// RoundUpToMultiple rounds x up to the nearest multiple of m, which must be
+// positive but need not be a power of 2. The returned bool is false if m is
+// not positive, or if the result is not representable by T. It is equivalent
+// to CeilTo.
+func RoundUpToMultiple[T constraints.Integer](x T, m T) (T, bool) {
+	return CeilTo(x, m)
 }
 
 // Precision truncates x to the given precision. If precision is < 0, x is
 // returned unchanged; if x == 0, x is rounded to the nearest integer;
 // otherwise, the precision of x is changed and the resulting value rounded.
+//
+// Precision operates on the binary value of x, so e.g. Precision(1.005, 2) is
+// 1 rather than 1.01. See PrecisionMode for decimal-correct rounding.
 func Precision[T constraints.Float](x T, precision int) T {
 	if precision < 0 {
 		return x
@@ -283,19 +444,182 @@ func Precision[T constraints.Float](x T, precision int) T {
 	return T(tmp / coeff)
 }
 
-// Fastrand returns a pseudorandom T in the range [0, 1<<32-1).
+// PrecisionMode rounds x to the given number of digits after the decimal point
+// using the given rounding mode. Negative digits round to the left of the
+// decimal point, e.g. -2 rounds to the nearest hundred.
+//
+// Rounding is performed on the shortest decimal representation of x rather
+// than on its binary value, so PrecisionMode(1.005, 2, RoundHalfAwayFromZero)
+// is 1.01, as written. The result is the T nearest to the rounded decimal
+// value. NaN, infinities, and zeros are returned unchanged, and NaN is
+// returned if mode is invalid. Results too large to be represented by T
+// become infinite.
+func PrecisionMode[T constraints.Float](x T, digits int, mode RoundingMode) T {
+	if !mode.valid() {
+		return T(math.NaN())
+	}
+
+	if x == 0 || math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+		return x
+	}
+
+	var (
+		bitSize = BitSize[T]()
+		d       = newDecimal(float64(x), bitSize)
+	)
+
+	// Keep every digit at or above the 10^-digits place. The exponent of any
+	// float is well within ±1000, so clamping digits avoids overflow without
+	// changing the result.
+	d.round(len(d.digits)+d.exp+Clamp(digits, -1000, 1000), mode)
+	return T(d.float(bitSize))
+}
+
+// SigFigs rounds x to n significant digits using DefaultRoundingMode. See
+// SigFigsMode for details.
+func SigFigs[T constraints.Float](x T, n int) T {
+	return SigFigsMode(x, n, DefaultRoundingMode)
+}
+
+// SigFigsMode rounds x to n significant digits using the given rounding mode,
+// e.g. SigFigsMode(0.012345, 2, RoundHalfEven) is 0.012. Like PrecisionMode,
+// rounding is performed on the shortest decimal representation of x, and
+// subnormal values are rounded the same as any other. NaN, infinities, and
+// zeros are returned unchanged, and NaN is returned if n is less than 1 or
+// mode is invalid. Results too large to be represented by T become infinite.
+func SigFigsMode[T constraints.Float](x T, n int, mode RoundingMode) T {
+	if n < 1 || !mode.valid() {
+		return T(math.NaN())
+	}
+
+	if x == 0 || math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+		return x
+	}
+
+	var (
+		bitSize = BitSize[T]()
+		d       = newDecimal(float64(x), bitSize)
+	)
+
+	d.round(n, mode)
+	return T(d.float(bitSize))
+}
+
+// FormatSigFigs formats x rounded to n significant digits using
+// DefaultRoundingMode, without any trailing digits introduced by the binary
+// representation of the result; e.g. FormatSigFigs(0.1+0.2, 3) is "0.3"
+// rather than "0.300" or "0.30000000000000004". Exponential notation is used
+// for very large and very small magnitudes, as with the 'g' format. If n is
+// less than 1, "NaN" is returned.
+func FormatSigFigs[T constraints.Float](x T, n int) string {
+	return strconv.FormatFloat(float64(SigFigs(x, n)), 'g', -1, BitSize[T]())
+}
+
+// Fastrand returns a pseudorandom T uniformly distributed over the
+// non-negative values of T for integer types, e.g. [0, 1<<64) for uint64 and
+// [0, 1<<63) for int64, or an integral T in the range [0, 1<<32) for floating
+// point types. It is safe for concurrent use and does not allocate.
 func Fastrand[T Unsigned32]() T {
-	return T(fastrand())
+	if IsFloat[T]() {
+		return T(rand.Uint32())
+	}
+
+	shift := 64 - BitSize[T]()
+	if IsSigned[T]() {
+		shift++
+	}
+
+	return T(rand.Uint64() >> shift)
 }
 
-// Fastrandn returns a pseudorandom T in the range [0, min(x, 1<<32-1)). If x
-// is greater than 1<<32-1, it will be clamped.
+// Fastrandn returns a pseudorandom T in the range [0, x), where x may be as
+// large as 1<<64-1. If x is less than 1, 0 is returned. It is safe for
+// concurrent use and does not allocate.
 func Fastrandn[T Unsigned32](x T) T {
-	return T(fastrandn(uint32(Clamp(x, 0, math.MaxUint32))))
+	n := ConvertSat[uint64](x)
+	if n == 0 {
+		return 0
+	}
+
+	return T(rand.Uint64N(n))
+}
+
+// Fastrand64 returns a pseudorandom T uniformly distributed over every value
+// of T, including negative values for signed types. It is safe for concurrent
+// use and does not allocate.
+func Fastrand64[T constraints.Integer]() T {
+	return T(rand.Uint64())
+}
+
+// Fastrandn64 returns a pseudorandom T uniformly distributed in [0, n). If n
+// is less than 1, 0 is returned. It is equivalent to Intn(FastSource, n).
+func Fastrandn64[T constraints.Integer](n T) T {
+	return Intn(FastSource, n)
+}
+
+// FastrandRange returns a pseudorandom T uniformly distributed in the
+// inclusive range [lo, hi]. If hi is less than lo, the bounds are swapped. It
+// is equivalent to IntRange(FastSource, lo, hi).
+func FastrandRange[T constraints.Integer](lo T, hi T) T {
+	return IntRange(FastSource, lo, hi)
+}
+
+// maxPowerOf2Shift returns the largest n such that 1<<n is representable by the
+// integer type T.
+func maxPowerOf2Shift[T Numeric]() int {
+	if IsSigned[T]() {
+		return BitSize[T]() - 2
+	}
+
+	return BitSize[T]() - 1
+}
+
+// sum128 returns the magnitude of the sum of x as a 128-bit integer, along
+// with whether the sum is negative. T must be an integer type.
+func sum128[T Numeric](x []T) (hi uint64, lo uint64, neg bool) {
+	var carry uint64
+
+	if IsSigned[T]() {
+		for _, n := range x {
+			v := int64(n)
+			lo, carry = bits.Add64(lo, uint64(v), 0)
+			hi += carry + uint64(v>>63)
+		}
+
+		if int64(hi) < 0 {
+			lo, carry = bits.Add64(^lo, 1, 0)
+			hi = ^hi + carry
+			neg = true
+		}
+
+		return hi, lo, neg
+	}
+
+	for _, n := range x {
+		lo, carry = bits.Add64(lo, uint64(n), 0)
+		hi += carry
+	}
+
+	return hi, lo, false
 }
 
-//go:linkname fastrand runtime.fastrand
-func fastrand() uint32
+// sumFloat64 returns the sum of x as a float64, reporting false if the sum
+// overflowed to an infinity that was not present in x.
+func sumFloat64[T Numeric](x []T) (float64, bool) {
+	var total float64
+	for _, n := range x {
+		total += float64(n)
+	}
+
+	if !math.IsInf(total, 0) {
+		return total, true
+	}
+
+	for _, n := range x {
+		if math.IsInf(float64(n), 0) {
+			return total, true
+		}
+	}
 
-//go:linkname fastrandn runtime.fastrandn
-func fastrandn(uint32) uint32
+	return total, false
+}
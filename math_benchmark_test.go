@@ -152,6 +152,65 @@ func BenchmarkMean(b *testing.B) {
 					math.MeanFloat64(numbers...)
 				}
 			})
+
+			b.Run("wide", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.MeanWide(numbers...)
+				}
+			})
+
+			b.Run("wide float64", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.MeanWideFloat64(numbers...)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	for _, size := range []int{8, 128, 1024, 65536} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			numbers := make([]float64, size)
+			for i := 0; i < len(numbers); i++ {
+				numbers[i] = float64(i) * 0.1
+			}
+
+			b.Run("naive", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.Sum(numbers...)
+				}
+			})
+
+			b.Run("kahan", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.KahanSum(numbers...)
+				}
+			})
+
+			b.Run("pairwise", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.PairwiseSum(numbers...)
+				}
+			})
+
+			b.Run("mean precise", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					math.MeanPrecise(numbers...)
+				}
+			})
 		})
 	}
 }
@@ -191,17 +250,112 @@ func BenchmarkClosestPowerOf2(b *testing.B) {
 }
 
 func BenchmarkFastrand(b *testing.B) {
-	b.ReportAllocs()
+	b.Run("serial", func(b *testing.B) {
+		b.ReportAllocs()
 
-	for i := 0; i < b.N; i++ {
-		math.Fastrand[int]()
-	}
+		for i := 0; i < b.N; i++ {
+			math.Fastrand[int]()
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		b.ReportAllocs()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				math.Fastrand[int]()
+			}
+		})
+	})
 }
 
 func BenchmarkFastrandn(b *testing.B) {
-	b.ReportAllocs()
+	bounds := []struct {
+		name  string
+		bound uint64
+	}{
+		{"32-bit", 1024},
+		{"64-bit", 1<<63 + 1<<62},
+	}
 
-	for i := 0; i < b.N; i++ {
-		math.Fastrandn(1024)
+	for _, tt := range bounds {
+		bound := tt.bound
+
+		b.Run(tt.name+"/serial", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				math.Fastrandn(bound)
+			}
+		})
+
+		b.Run(tt.name+"/parallel", func(b *testing.B) {
+			b.ReportAllocs()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					math.Fastrandn(bound)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkRNG(b *testing.B) {
+	sources := []struct {
+		name string
+		src  math.Source
+	}{
+		{"xoshiro", math.NewRNG(1)},
+		{"fast", math.FastSource},
+	}
+
+	for _, tt := range sources {
+		src := tt.src
+
+		b.Run(tt.name+"/Uint64", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				src.Uint64()
+			}
+		})
+
+		b.Run(tt.name+"/Intn", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				math.Intn(src, 1000)
+			}
+		})
+	}
+}
+
+func BenchmarkWeighted(b *testing.B) {
+	weights := make([]float64, 1000)
+	for i := range weights {
+		weights[i] = float64(i%10 + 1)
 	}
+
+	b.Run("WeightedIndex", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			math.WeightedIndex(weights)
+		}
+	})
+
+	b.Run("WeightedChooser", func(b *testing.B) {
+		chooser, err := math.NewWeightedChooser(weights)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			chooser.Choose()
+		}
+	})
 }
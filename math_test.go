@@ -21,11 +21,15 @@
 package math_test
 
 import (
+	stdmath "math"
+	"math/bits"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.mway.dev/math"
+	"golang.org/x/exp/constraints"
 )
 
 func TestAbs(t *testing.T) {
@@ -205,6 +209,79 @@ func TestMeanFloat64(t *testing.T) {
 	require.Equal(t, float64(3.5), math.MeanFloat64[float64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
 }
 
+func TestMeanWide(t *testing.T) {
+	requireOK(t, 3)(math.MeanWide(1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, int8(3))(math.MeanWide[int8](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, int8(100))(math.MeanWide[int8](100, 100, 100))
+	requireOK(t, int8(-128))(math.MeanWide[int8](-128, -128, -128))
+	requireOK(t, int8(-1))(math.MeanWide[int8](-1, -2))
+	requireOK(t, int16(3))(math.MeanWide[int16](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, int16(30000))(math.MeanWide[int16](30000, 30000, 30000))
+	requireOK(t, int32(3))(math.MeanWide[int32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, int64(3))(math.MeanWide[int64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, int64(stdmath.MaxInt64))(
+		math.MeanWide[int64](stdmath.MaxInt64, stdmath.MaxInt64, stdmath.MaxInt64),
+	)
+	requireOK(t, int64(stdmath.MinInt64))(
+		math.MeanWide[int64](stdmath.MinInt64, stdmath.MinInt64, stdmath.MinInt64),
+	)
+	requireOK(t, int64(0))(math.MeanWide[int64](stdmath.MinInt64, stdmath.MaxInt64))
+	requireOK(t, uint(3))(math.MeanWide[uint](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, uint8(3))(math.MeanWide[uint8](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, uint8(255))(math.MeanWide[uint8](255, 255, 255))
+	requireOK(t, uint16(3))(math.MeanWide[uint16](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, uint32(3))(math.MeanWide[uint32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, uint64(3))(math.MeanWide[uint64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, uint64(stdmath.MaxUint64))(
+		math.MeanWide[uint64](stdmath.MaxUint64, stdmath.MaxUint64, stdmath.MaxUint64),
+	)
+	requireOK(t, time.Duration(3))(math.MeanWide[time.Duration](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, float32(3.5))(math.MeanWide[float32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, float32(stdmath.MaxFloat32))(
+		math.MeanWide[float32](stdmath.MaxFloat32, stdmath.MaxFloat32),
+	)
+	requireOK(t, float64(3.5))(math.MeanWide[float64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, stdmath.Inf(1))(math.MeanWide(stdmath.Inf(1), 1.0))
+
+	_, ok := math.MeanWide[int]()
+	require.False(t, ok)
+
+	_, ok = math.MeanWide(stdmath.MaxFloat64, stdmath.MaxFloat64)
+	require.False(t, ok)
+}
+
+func TestMeanWideFloat64(t *testing.T) {
+	requireOK(t, 3.5)(math.MeanWideFloat64(1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[int8](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 100.0)(math.MeanWideFloat64[int8](100, 100, 100))
+	requireOK(t, -1.5)(math.MeanWideFloat64[int8](-1, -2))
+	requireOK(t, 3.5)(math.MeanWideFloat64[int16](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[int32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[int64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, float64(stdmath.MinInt64))(
+		math.MeanWideFloat64[int64](stdmath.MinInt64, stdmath.MinInt64),
+	)
+	requireOK(t, -0.5)(math.MeanWideFloat64[int64](stdmath.MinInt64, stdmath.MaxInt64))
+	requireOK(t, 3.5)(math.MeanWideFloat64[uint](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[uint8](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 254.5)(math.MeanWideFloat64[uint8](254, 255))
+	requireOK(t, 3.5)(math.MeanWideFloat64[uint16](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[uint32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[uint64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, float64(stdmath.MaxUint64))(
+		math.MeanWideFloat64[uint64](stdmath.MaxUint64, stdmath.MaxUint64),
+	)
+	requireOK(t, 3.5)(math.MeanWideFloat64[time.Duration](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[float32](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+	requireOK(t, 3.5)(math.MeanWideFloat64[float64](1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6))
+
+	_, ok := math.MeanWideFloat64[uint8]()
+	require.False(t, ok)
+
+	_, ok = math.MeanWideFloat64(stdmath.MaxFloat64, stdmath.MaxFloat64)
+	require.False(t, ok)
+}
+
 func TestClamp(t *testing.T) {
 	require.Equal(t, 5, math.Clamp(5, 1, 10))
 	require.Equal(t, 10, math.Clamp(15, 1, 10))
@@ -282,6 +359,36 @@ func TestNextPowerOf2(t *testing.T) {
 		require.Equal(t, float64(pair[1]), math.NextPowerOf2(float64(pair[0])))
 		require.Equal(t, time.Duration(pair[1]), math.NextPowerOf2(time.Duration(pair[0])))
 	}
+
+	testNextPowerOf2Top[int](t, 1<<(strconv.IntSize-2), stdmath.MaxInt)
+	testNextPowerOf2Top[int8](t, 1<<6, stdmath.MaxInt8)
+	testNextPowerOf2Top[int16](t, 1<<14, stdmath.MaxInt16)
+	testNextPowerOf2Top[int32](t, 1<<30, stdmath.MaxInt32)
+	testNextPowerOf2Top[int64](t, 1<<62, stdmath.MaxInt64)
+	testNextPowerOf2Top[uint](t, 1<<(strconv.IntSize-1), stdmath.MaxUint)
+	testNextPowerOf2Top[uint8](t, 1<<7, stdmath.MaxUint8)
+	testNextPowerOf2Top[uint16](t, 1<<15, stdmath.MaxUint16)
+	testNextPowerOf2Top[uint32](t, 1<<31, stdmath.MaxUint32)
+	testNextPowerOf2Top[uint64](t, 1<<63, stdmath.MaxUint64)
+	testNextPowerOf2Top[time.Duration](t, 1<<62, stdmath.MaxInt64)
+
+	// Integers above 2^53 cannot be represented exactly as float64.
+	require.Equal(t, int64(1<<54), math.NextPowerOf2[int64](1<<53+1))
+	require.Equal(t, uint64(1<<63), math.NextPowerOf2[uint64](1<<62+1))
+
+	require.Equal(t, float32(4), math.NextPowerOf2[float32](2.5))
+	require.Equal(t, float32(1), math.NextPowerOf2[float32](0.75))
+	require.Equal(t, float32(0x1p127), math.NextPowerOf2[float32](0x1p127))
+	require.Equal(t, float32(0x1p127), math.NextPowerOf2[float32](0x1p126*1.5))
+	require.Equal(t, float32(0), math.NextPowerOf2[float32](stdmath.MaxFloat32))
+	require.Equal(t, float64(4), math.NextPowerOf2(2.5))
+	require.Equal(t, float64(0x1p1023), math.NextPowerOf2(0x1p1023))
+	require.Equal(t, float64(0x1p1023), math.NextPowerOf2(0x1p1022*1.5))
+	require.Equal(t, float64(0x1p997), math.NextPowerOf2(1e300))
+	require.Equal(t, float64(0), math.NextPowerOf2(stdmath.MaxFloat64))
+	require.Equal(t, float64(0), math.NextPowerOf2(stdmath.Inf(1)))
+	require.Equal(t, float64(0), math.NextPowerOf2(stdmath.NaN()))
+	require.Equal(t, float64(1), math.NextPowerOf2(stdmath.Inf(-1)))
 }
 
 func TestClosestPowerOf2(t *testing.T) {
@@ -337,6 +444,295 @@ func TestClosestPowerOf2(t *testing.T) {
 		require.Equal(t, float64(pair[1]), math.ClosestPowerOf2(float64(pair[0])))
 		require.Equal(t, time.Duration(pair[1]), math.ClosestPowerOf2(time.Duration(pair[0])))
 	}
+
+	require.Equal(t, int64(1<<53), math.ClosestPowerOf2[int64](1<<53+1))
+	require.Equal(t, uint64(1<<63), math.ClosestPowerOf2[uint64](1<<62+1<<61))
+	require.Equal(t, uint64(1<<62), math.ClosestPowerOf2[uint64](1<<62+1<<61-1))
+
+	require.Equal(t, float32(2), math.ClosestPowerOf2[float32](2.5))
+	require.Equal(t, float32(4), math.ClosestPowerOf2[float32](3))
+	require.Equal(t, float32(0x1p127), math.ClosestPowerOf2[float32](stdmath.MaxFloat32))
+	require.Equal(t, float64(0x1p1023), math.ClosestPowerOf2(stdmath.MaxFloat64))
+	require.Equal(t, float64(0x1p996), math.ClosestPowerOf2(1e300))
+	require.Equal(t, float64(0), math.ClosestPowerOf2(stdmath.Inf(1)))
+	require.Equal(t, float64(0), math.ClosestPowerOf2(stdmath.NaN()))
+}
+
+func testNextPowerOf2Top[T constraints.Integer](t *testing.T, maxPow T, maxVal T) {
+	require.Equal(t, maxPow, math.NextPowerOf2(maxPow))
+	require.Equal(t, maxPow, math.NextPowerOf2(maxPow/2+1))
+	require.Equal(t, T(0), math.NextPowerOf2(maxPow+1))
+	require.Equal(t, T(0), math.NextPowerOf2(maxVal))
+
+	require.Equal(t, maxPow, math.ClosestPowerOf2(maxPow))
+	require.Equal(t, maxPow, math.ClosestPowerOf2(maxVal))
+	require.Equal(t, maxPow, math.ClosestPowerOf2(maxPow/2+maxPow/4))
+	require.Equal(t, maxPow/2, math.ClosestPowerOf2(maxPow/2+maxPow/4-1))
+}
+
+func TestIsPowerOf2(t *testing.T) {
+	testIsPowerOf2[int](t)
+	testIsPowerOf2[int8](t)
+	testIsPowerOf2[int16](t)
+	testIsPowerOf2[int32](t)
+	testIsPowerOf2[int64](t)
+	testIsPowerOf2[uint](t)
+	testIsPowerOf2[uint8](t)
+	testIsPowerOf2[uint16](t)
+	testIsPowerOf2[uint32](t)
+	testIsPowerOf2[uint64](t)
+	testIsPowerOf2[time.Duration](t)
+}
+
+func TestPrevPowerOf2(t *testing.T) {
+	testPrevPowerOf2[int](t)
+	testPrevPowerOf2[int8](t)
+	testPrevPowerOf2[int16](t)
+	testPrevPowerOf2[int32](t)
+	testPrevPowerOf2[int64](t)
+	testPrevPowerOf2[uint](t)
+	testPrevPowerOf2[uint8](t)
+	testPrevPowerOf2[uint16](t)
+	testPrevPowerOf2[uint32](t)
+	testPrevPowerOf2[uint64](t)
+	testPrevPowerOf2[time.Duration](t)
+}
+
+func TestLog2Floor(t *testing.T) {
+	testLog2Floor[int](t)
+	testLog2Floor[int8](t)
+	testLog2Floor[int16](t)
+	testLog2Floor[int32](t)
+	testLog2Floor[int64](t)
+	testLog2Floor[uint](t)
+	testLog2Floor[uint8](t)
+	testLog2Floor[uint16](t)
+	testLog2Floor[uint32](t)
+	testLog2Floor[uint64](t)
+	testLog2Floor[time.Duration](t)
+}
+
+func TestLog2Ceil(t *testing.T) {
+	testLog2Ceil[int](t)
+	testLog2Ceil[int8](t)
+	testLog2Ceil[int16](t)
+	testLog2Ceil[int32](t)
+	testLog2Ceil[int64](t)
+	testLog2Ceil[uint](t)
+	testLog2Ceil[uint8](t)
+	testLog2Ceil[uint16](t)
+	testLog2Ceil[uint32](t)
+	testLog2Ceil[uint64](t)
+	testLog2Ceil[time.Duration](t)
+}
+
+func TestAlignUp(t *testing.T) {
+	testAlignUp[int](t)
+	testAlignUp[int8](t)
+	testAlignUp[int16](t)
+	testAlignUp[int32](t)
+	testAlignUp[int64](t)
+	testAlignUp[uint](t)
+	testAlignUp[uint8](t)
+	testAlignUp[uint16](t)
+	testAlignUp[uint32](t)
+	testAlignUp[uint64](t)
+	testAlignUp[time.Duration](t)
+}
+
+func TestAlignDown(t *testing.T) {
+	testAlignDown[int](t)
+	testAlignDown[int8](t)
+	testAlignDown[int16](t)
+	testAlignDown[int32](t)
+	testAlignDown[int64](t)
+	testAlignDown[uint](t)
+	testAlignDown[uint8](t)
+	testAlignDown[uint16](t)
+	testAlignDown[uint32](t)
+	testAlignDown[uint64](t)
+	testAlignDown[time.Duration](t)
+}
+
+func TestRoundUpToMultiple(t *testing.T) {
+	testRoundUpToMultiple[int](t)
+	testRoundUpToMultiple[int8](t)
+	testRoundUpToMultiple[int16](t)
+	testRoundUpToMultiple[int32](t)
+	testRoundUpToMultiple[int64](t)
+	testRoundUpToMultiple[uint](t)
+	testRoundUpToMultiple[uint8](t)
+	testRoundUpToMultiple[uint16](t)
+	testRoundUpToMultiple[uint32](t)
+	testRoundUpToMultiple[uint64](t)
+	testRoundUpToMultiple[time.Duration](t)
+}
+
+func testIsPowerOf2[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+	)
+
+	require.False(t, math.IsPowerOf2[T](0))
+	require.False(t, math.IsPowerOf2(maxVal))
+	require.False(t, math.IsPowerOf2(minVal))
+	require.False(t, math.IsPowerOf2(minVal/2))
+
+	for shift := 0; shift <= math.BitSize[T]()-1; shift++ {
+		x := T(1) << shift
+		if x < 0 {
+			break
+		}
+
+		require.True(t, math.IsPowerOf2(x), x)
+		if x > 2 {
+			require.False(t, math.IsPowerOf2(x-1), x-1)
+			require.False(t, math.IsPowerOf2(x+1), x+1)
+		}
+	}
+}
+
+func testPrevPowerOf2[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+		maxPow = maxVal/2 + 1
+	)
+
+	require.Equal(t, T(0), math.PrevPowerOf2[T](0))
+	require.Equal(t, T(0), math.PrevPowerOf2(minVal))
+	require.Equal(t, T(1), math.PrevPowerOf2[T](1))
+	require.Equal(t, T(2), math.PrevPowerOf2[T](3))
+	require.Equal(t, T(4), math.PrevPowerOf2[T](4))
+	require.Equal(t, T(4), math.PrevPowerOf2[T](7))
+	require.Equal(t, T(64), math.PrevPowerOf2[T](100))
+	require.Equal(t, maxPow, math.PrevPowerOf2(maxVal))
+	require.Equal(t, maxPow, math.PrevPowerOf2(maxPow))
+	require.Equal(t, maxPow/2, math.PrevPowerOf2(maxPow-1))
+}
+
+func testLog2Floor[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+		bits   = bits.Len64(uint64(maxVal))
+	)
+
+	require.Equal(t, -1, math.Log2Floor[T](0))
+	require.Equal(t, -1, math.Log2Floor(minVal))
+	require.Equal(t, 0, math.Log2Floor[T](1))
+	require.Equal(t, 1, math.Log2Floor[T](2))
+	require.Equal(t, 1, math.Log2Floor[T](3))
+	require.Equal(t, 2, math.Log2Floor[T](4))
+	require.Equal(t, 6, math.Log2Floor[T](127))
+	require.Equal(t, bits-1, math.Log2Floor(maxVal))
+	require.Equal(t, bits-1, math.Log2Floor(maxVal/2+1))
+	require.Equal(t, bits-2, math.Log2Floor(maxVal/2))
+}
+
+func testLog2Ceil[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+		bits   = bits.Len64(uint64(maxVal))
+	)
+
+	require.Equal(t, -1, math.Log2Ceil[T](0))
+	require.Equal(t, -1, math.Log2Ceil(minVal))
+	require.Equal(t, 0, math.Log2Ceil[T](1))
+	require.Equal(t, 1, math.Log2Ceil[T](2))
+	require.Equal(t, 2, math.Log2Ceil[T](3))
+	require.Equal(t, 2, math.Log2Ceil[T](4))
+	require.Equal(t, 7, math.Log2Ceil[T](127))
+	require.Equal(t, bits, math.Log2Ceil(maxVal))
+	require.Equal(t, bits-1, math.Log2Ceil(maxVal/2+1))
+	require.Equal(t, bits-1, math.Log2Ceil(maxVal/2))
+}
+
+func testAlignUp[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+		maxPow = maxVal/2 + 1
+	)
+
+	requireOK(t, T(0))(math.AlignUp[T](0, 8))
+	requireOK(t, T(8))(math.AlignUp[T](1, 8))
+	requireOK(t, T(8))(math.AlignUp[T](8, 8))
+	requireOK(t, T(16))(math.AlignUp[T](9, 8))
+	requireOK(t, T(5))(math.AlignUp[T](5, 1))
+	requireOK(t, maxPow)(math.AlignUp(maxPow, maxPow))
+	requireOK(t, maxPow)(math.AlignUp(1, maxPow))
+	requireOK(t, maxVal-maxVal%16)(math.AlignUp(maxVal-16, 16))
+	requireOK(t, minVal)(math.AlignUp(minVal, 8))
+	requireNotOK[T](t)(math.AlignUp(maxVal, 2))
+	requireNotOK[T](t)(math.AlignUp(maxPow+1, maxPow))
+	requireNotOK[T](t)(math.AlignUp[T](5, 0))
+	requireNotOK[T](t)(math.AlignUp[T](5, 3))
+	requireNotOK[T](t)(math.AlignUp[T](5, 12))
+
+	if minVal < 0 {
+		requireOK(t, minVal+8)(math.AlignUp(minVal+1, 8))
+		requireOK(t, minVal+maxPow)(math.AlignUp(minVal+1, maxPow))
+		requireNotOK[T](t)(math.AlignUp(5, minVal))
+	}
+}
+
+func testAlignDown[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+		maxPow = maxVal/2 + 1
+	)
+
+	requireOK(t, T(0))(math.AlignDown[T](0, 8))
+	requireOK(t, T(0))(math.AlignDown[T](1, 8))
+	requireOK(t, T(8))(math.AlignDown[T](8, 8))
+	requireOK(t, T(8))(math.AlignDown[T](15, 8))
+	requireOK(t, T(5))(math.AlignDown[T](5, 1))
+	requireOK(t, maxPow)(math.AlignDown(maxVal, maxPow))
+	requireOK(t, T(0))(math.AlignDown(maxPow-1, maxPow))
+	requireOK(t, maxVal-1)(math.AlignDown(maxVal, 2))
+	requireOK(t, minVal)(math.AlignDown(minVal, 8))
+	requireNotOK[T](t)(math.AlignDown[T](5, 0))
+	requireNotOK[T](t)(math.AlignDown[T](5, 3))
+
+	if minVal < 0 {
+		requireOK(t, minVal)(math.AlignDown(minVal+1, 8))
+		requireOK(t, minVal)(math.AlignDown(minVal+1, maxPow))
+		requireNotOK[T](t)(math.AlignDown(5, minVal))
+	}
+}
+
+func testRoundUpToMultiple[T constraints.Integer](t *testing.T) {
+	var (
+		minVal = math.MinValue[T]()
+		maxVal = math.MaxValue[T]()
+	)
+
+	requireOK(t, T(0))(math.RoundUpToMultiple[T](0, 3))
+	requireOK(t, T(3))(math.RoundUpToMultiple[T](1, 3))
+	requireOK(t, T(3))(math.RoundUpToMultiple[T](3, 3))
+	requireOK(t, T(6))(math.RoundUpToMultiple[T](4, 3))
+	requireOK(t, T(100))(math.RoundUpToMultiple[T](91, 10))
+	requireOK(t, maxVal)(math.RoundUpToMultiple(maxVal, maxVal))
+	requireOK(t, maxVal)(math.RoundUpToMultiple(1, maxVal))
+	requireOK(t, maxVal-maxVal%10)(math.RoundUpToMultiple(maxVal-maxVal%10, 10))
+	requireNotOK[T](t)(math.RoundUpToMultiple(maxVal-maxVal%10+1, 10))
+	requireNotOK[T](t)(math.RoundUpToMultiple(maxVal, maxVal-1))
+	requireNotOK[T](t)(math.RoundUpToMultiple[T](5, 0))
+
+	if minVal < 0 {
+		// Negative values are built at runtime so that the constants remain
+		// valid for unsigned instantiations.
+		var zero T
+		requireOK(t, zero-3)(math.RoundUpToMultiple(zero-5, 3))
+		requireOK(t, zero-6)(math.RoundUpToMultiple(zero-6, 3))
+		requireOK(t, zero)(math.RoundUpToMultiple(zero-2, 3))
+		requireOK(t, minVal)(math.RoundUpToMultiple(minVal, 2))
+		requireNotOK[T](t)(math.RoundUpToMultiple(5, zero-3))
+	}
 }
 
 func TestPrecision(t *testing.T) {
@@ -348,6 +744,226 @@ func TestPrecision(t *testing.T) {
 	require.Equal(t, 1.2345, math.Precision(1.2345, 4))
 }
 
+func TestPrecisionMode(t *testing.T) {
+	const (
+		away  = math.RoundHalfAwayFromZero
+		even  = math.RoundHalfEven
+		up    = math.RoundHalfUp
+		down  = math.RoundHalfDown
+		floor = math.RoundFloor
+		ceil  = math.RoundCeil
+		trunc = math.RoundTruncate
+	)
+
+	cases := []struct {
+		give   float64
+		digits int
+		mode   math.RoundingMode
+		want   float64
+	}{
+		// Ties that are inexact in binary.
+		{1.005, 2, away, 1.01},
+		{1.005, 2, even, 1.0},
+		{1.005, 2, up, 1.01},
+		{1.005, 2, down, 1.0},
+		{1.005, 2, floor, 1.0},
+		{1.005, 2, ceil, 1.01},
+		{1.005, 2, trunc, 1.0},
+		{-1.005, 2, away, -1.01},
+		{-1.005, 2, even, -1.0},
+		{-1.005, 2, up, -1.0},
+		{-1.005, 2, down, -1.01},
+		{-1.005, 2, floor, -1.01},
+		{-1.005, 2, ceil, -1.0},
+		{-1.005, 2, trunc, -1.0},
+		{0.125, 2, even, 0.12},
+		{0.135, 2, even, 0.14},
+		{2.675, 2, away, 2.68},
+
+		// Ties and non-ties at zero digits.
+		{2.5, 0, away, 3},
+		{2.5, 0, even, 2},
+		{3.5, 0, even, 4},
+		{-2.5, 0, up, -2},
+		{-2.5, 0, down, -3},
+		{2.4, 0, away, 2},
+		{2.6, 0, trunc, 2},
+		{2.4, 0, ceil, 3},
+		{-2.4, 0, floor, -3},
+
+		// Negative digits.
+		{1234.5, -2, away, 1200},
+		{1250, -2, away, 1300},
+		{1250, -2, even, 1200},
+		{1350, -2, even, 1400},
+		{9999, -2, away, 10000},
+		{5, -1, away, 10},
+		{5, -1, even, 0},
+		{5, -1, down, 0},
+		{4, -3, ceil, 1000},
+		{-4, -3, floor, -1000},
+		{4, -3, away, 0},
+
+		// Values far below the requested precision.
+		{0.004, 2, away, 0},
+		{0.004, 2, ceil, 0.01},
+		{0.0004, 2, ceil, 0.01},
+		{0.0004, 2, away, 0},
+		{-0.0004, 2, floor, -0.01},
+		{1e-300, 2, ceil, 0.01},
+
+		// Values already at or beyond the requested precision.
+		{1.2345, 4, away, 1.2345},
+		{1.2345, 10, trunc, 1.2345},
+		{1.2345, stdmath.MaxInt, trunc, 1.2345},
+		{1e300, 2, away, 1e300},
+		{123, stdmath.MinInt, away, 0},
+
+		// Subnormals and the top of the range.
+		{5e-324, 323, away, 1e-323},
+		{5e-324, 323, trunc, 0},
+		{stdmath.MaxFloat64, -308, trunc, 1e308},
+		{stdmath.MaxFloat64, -308, away, stdmath.Inf(1)},
+		{-stdmath.MaxFloat64, -308, away, stdmath.Inf(-1)},
+	}
+
+	for _, tt := range cases {
+		got := math.PrecisionMode(tt.give, tt.digits, tt.mode)
+		require.Equal(t, tt.want, got, "%v, %d, %d", tt.give, tt.digits, tt.mode)
+	}
+}
+
+func TestPrecisionModeSpecialValues(t *testing.T) {
+	require.True(t, stdmath.IsNaN(math.PrecisionMode(stdmath.NaN(), 2, math.RoundCeil)))
+	require.True(t, stdmath.IsInf(math.PrecisionMode(stdmath.Inf(1), 2, math.RoundFloor), 1))
+	require.True(t, stdmath.IsInf(math.PrecisionMode(stdmath.Inf(-1), 2, math.RoundCeil), -1))
+	require.True(t, stdmath.Signbit(math.PrecisionMode(stdmath.Copysign(0, -1), 2, math.RoundCeil)))
+	require.True(t, stdmath.Signbit(math.PrecisionMode(-0.004, 2, math.RoundHalfAwayFromZero)))
+	require.True(t, stdmath.IsNaN(math.PrecisionMode(1.5, 2, 0)))
+	require.True(t, stdmath.IsNaN(math.PrecisionMode(1.5, 2, math.RoundTruncate+1)))
+
+	require.Equal(
+		t,
+		float32(1.01),
+		math.PrecisionMode(float32(1.005), 2, math.RoundHalfAwayFromZero),
+	)
+	require.Equal(
+		t,
+		float32(1.2e38),
+		math.PrecisionMode(float32(1.15e38), -37, math.RoundHalfEven),
+	)
+	require.True(t, stdmath.IsInf(
+		float64(math.PrecisionMode(math.MaxValue[float32](), -38, math.RoundCeil)),
+		1,
+	))
+}
+
+func TestSigFigs(t *testing.T) {
+	// Variables rather than constants, so that the sum is inexact.
+	tenth, fifth := 0.1, 0.2
+
+	cases := []struct {
+		give float64
+		n    int
+		want float64
+	}{
+		{1.2345, 1, 1},
+		{1.2345, 2, 1.2},
+		{1.2345, 4, 1.235},
+		{1.2345, 5, 1.2345},
+		{1.2345, 20, 1.2345},
+		{0.012345, 2, 0.012},
+		{0.015, 1, 0.02},
+		{-0.015, 1, -0.02},
+		{123456, 2, 120000},
+		{125000, 2, 130000},
+		{999.5, 3, 1000},
+		{9.999e-10, 2, 1e-9},
+		{6.02214076e23, 3, 6.02e23},
+		{tenth + fifth, 3, 0.3},
+		{5e-324, 1, 5e-324},
+		{1.5e-323, 1, 2e-323},
+		{2.2250738585072014e-308, 3, 2.23e-308},
+		{stdmath.MaxFloat64, 1, stdmath.Inf(1)},
+	}
+
+	for _, tt := range cases {
+		require.Equal(t, tt.want, math.SigFigs(tt.give, tt.n), "%v, %d", tt.give, tt.n)
+	}
+
+	require.Equal(t, 0.012, math.SigFigsMode(0.0125, 2, math.RoundHalfEven))
+	require.Equal(t, 0.014, math.SigFigsMode(0.0135, 2, math.RoundHalfEven))
+	require.Equal(t, 0.013, math.SigFigsMode(0.0125, 2, math.RoundHalfAwayFromZero))
+	require.Equal(t, -0.012, math.SigFigsMode(-0.0125, 2, math.RoundHalfUp))
+	require.Equal(t, 0.019, math.SigFigsMode(0.0181, 2, math.RoundCeil))
+	require.Equal(t, 0.018, math.SigFigsMode(0.0189, 2, math.RoundTruncate))
+	require.Equal(t, 1.79e308, math.SigFigsMode(stdmath.MaxFloat64, 3, math.RoundTruncate))
+	require.Equal(t, float32(3.14), math.SigFigs(float32(stdmath.Pi), 3))
+
+	require.Equal(t, 0.0, math.SigFigs(0.0, 3))
+	require.True(t, stdmath.Signbit(math.SigFigs(stdmath.Copysign(0, -1), 3)))
+	require.True(t, stdmath.IsNaN(math.SigFigs(stdmath.NaN(), 3)))
+	require.True(t, stdmath.IsInf(math.SigFigs(stdmath.Inf(-1), 3), -1))
+	require.True(t, stdmath.IsNaN(math.SigFigs(1.5, 0)))
+	require.True(t, stdmath.IsNaN(math.SigFigsMode(1.5, 2, 0)))
+}
+
+func TestFormatSigFigs(t *testing.T) {
+	tenth, fifth := 0.1, 0.2
+
+	cases := []struct {
+		give float64
+		n    int
+		want string
+	}{
+		{tenth + fifth, 3, "0.3"},
+		{tenth + fifth, 17, "0.30000000000000004"},
+		{1.2345, 3, "1.23"},
+		{1.0, 3, "1"},
+		{-0.0012345, 2, "-0.0012"},
+		{123456, 2, "120000"},
+		{6.02214076e23, 3, "6.02e+23"},
+		{1.602176634e-19, 4, "1.602e-19"},
+		{5e-324, 3, "5e-324"},
+		{0, 3, "0"},
+		{stdmath.Inf(1), 3, "+Inf"},
+		{stdmath.NaN(), 3, "NaN"},
+		{1.5, 0, "NaN"},
+	}
+
+	for _, tt := range cases {
+		require.Equal(t, tt.want, math.FormatSigFigs(tt.give, tt.n), "%v, %d", tt.give, tt.n)
+	}
+
+	require.Equal(t, "0.3", math.FormatSigFigs(float32(tenth)+float32(fifth), 3))
+	require.Equal(t, "3.1415927", math.FormatSigFigs(float32(stdmath.Pi), 10))
+}
+
+func TestPrecisionModeMatchesStdlib(t *testing.T) {
+	modes := map[math.RoundingMode]func(float64) float64{
+		math.RoundHalfAwayFromZero: stdmath.Round,
+		math.RoundHalfEven:         stdmath.RoundToEven,
+		math.RoundFloor:            stdmath.Floor,
+		math.RoundCeil:             stdmath.Ceil,
+		math.RoundTruncate:         stdmath.Trunc,
+	}
+
+	values := []float64{0.5, 1.5, 2.5, -0.5, -1.5, 0.49999999999999994, 1 << 52}
+	for i := 0; i < 10000; i++ {
+		var (
+			mag = float64(math.Fastrandn[uint32](40)) - 20
+			x   = (float64(math.Fastrand[uint32]())/(1<<32) - 0.5) * stdmath.Pow(2, mag)
+		)
+		values = append(values, x, stdmath.Trunc(x)+0.5)
+	}
+
+	for mode, fn := range modes {
+		for _, x := range values {
+			require.Equal(t, fn(x), math.PrecisionMode(x, 0, mode), "%v, %d", x, mode)
+		}
+	}
+}
+
 func TestFastrand(t *testing.T) {
 	var (
 		reported = make(map[int64]struct{})
@@ -359,6 +975,23 @@ func TestFastrand(t *testing.T) {
 	}
 
 	require.Len(t, reported, wantLen)
+
+	// Values span the full range of 64-bit types.
+	var (
+		unsignedHigh uint64
+		signedHigh   int64
+	)
+
+	for i := 0; i < wantLen; i++ {
+		signed := math.Fastrand[int64]()
+		require.GreaterOrEqual(t, signed, int64(0))
+
+		unsignedHigh |= math.Fastrand[uint64]()
+		signedHigh |= signed
+	}
+
+	require.Equal(t, uint64(stdmath.MaxUint64), unsignedHigh)
+	require.Equal(t, int64(stdmath.MaxInt64), signedHigh)
 }
 
 func TestFastrandn(t *testing.T) {
@@ -376,3 +1009,150 @@ func TestFastrandn(t *testing.T) {
 
 	require.InDelta(t, wantLen, len(reported), float64(wantLen)/float64(100))
 }
+
+func TestFastrand64(t *testing.T) {
+	const draws = 1 << 16
+
+	testUniform(t, 256, draws, func() int { return int(math.Fastrand64[uint8]()) })
+	testUniform(t, 256, draws, func() int { return int(math.Fastrand64[int8]()) + 128 })
+	testUniform(t, 16, draws, func() int { return int(math.Fastrand64[uint64]() >> 60) })
+	testUniform(t, 16, draws, func() int { return int(math.Fastrand64[int64]()>>60) + 8 })
+	testUniform(t, 16, draws, func() int { return int(math.Fastrand64[uint16]() & 15) })
+}
+
+func TestFastrandn64(t *testing.T) {
+	const draws = 1 << 16
+
+	testUniform(t, 10, draws, func() int { return math.Fastrandn64(10) })
+	testUniform(t, 3, draws, func() int { return int(math.Fastrandn64[int64](3)) })
+	testUniform(t, 100, draws, func() int { return int(math.Fastrandn64[uint8](100)) })
+
+	// A bound just above 1<<63 maximizes the rejection rate; the second-highest
+	// bit of the result should still be evenly split.
+	bound := uint64(1<<63 + 1)
+	testUniform(t, 2, draws, func() int {
+		n := math.Fastrandn64(bound)
+		require.Less(t, n, bound)
+		return int(n >> 62)
+	})
+
+	require.Zero(t, math.Fastrandn64(0))
+	require.Zero(t, math.Fastrandn64(-1))
+	require.Zero(t, math.Fastrandn64[int8](stdmath.MinInt8))
+	require.Zero(t, math.Fastrandn64[uint8](1))
+}
+
+func TestFastrandRange(t *testing.T) {
+	const draws = 1 << 16
+
+	testUniform(t, 11, draws, func() int { return math.FastrandRange(-5, 5) + 5 })
+	testUniform(t, 11, draws, func() int { return math.FastrandRange(5, -5) + 5 })
+	testUniform(t, 256, draws, func() int {
+		return int(math.FastrandRange[int8](stdmath.MinInt8, stdmath.MaxInt8)) + 128
+	})
+	testUniform(t, 256, draws, func() int {
+		return int(math.FastrandRange[uint8](0, stdmath.MaxUint8))
+	})
+	testUniform(t, 16, draws, func() int {
+		return int(math.FastrandRange[int64](stdmath.MinInt64, stdmath.MaxInt64)>>60) + 8
+	})
+	testUniform(t, 7, draws, func() int {
+		return int(math.FastrandRange[uint64](stdmath.MaxUint64-6, stdmath.MaxUint64) -
+			(stdmath.MaxUint64 - 6))
+	})
+	testUniform(t, 4, draws, func() int {
+		return int(math.FastrandRange[int64](stdmath.MinInt64, stdmath.MinInt64+3) -
+			stdmath.MinInt64)
+	})
+
+	require.Equal(t, -3, math.FastrandRange(-3, -3))
+	require.Equal(t, uint8(7), math.FastrandRange[uint8](7, 7))
+}
+
+// testUniform draws values in [0, buckets) from fn and asserts that their
+// distribution passes a chi-square goodness-of-fit test for uniformity at a
+// significance level of 0.0001.
+func testUniform(t *testing.T, buckets int, draws int, fn func() int) {
+	t.Helper()
+
+	counts := make([]int, buckets)
+	for i := 0; i < draws; i++ {
+		x := fn()
+		require.True(t, x >= 0 && x < buckets, x)
+		counts[x]++
+	}
+
+	testUniformCounts(t, counts)
+}
+
+// testUniformCounts asserts that counts pass a chi-square goodness-of-fit test
+// for uniformity at a significance level of 0.0001.
+func testUniformCounts(t *testing.T, counts []int) {
+	t.Helper()
+
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+
+	var (
+		want = float64(total) / float64(len(counts))
+		chi2 float64
+	)
+
+	for _, c := range counts {
+		d := float64(c) - want
+		chi2 += d * d / want
+	}
+
+	require.Less(t, chi2, chiSquareCritical(len(counts)-1), counts)
+}
+
+// chiSquareCritical returns the Wilson-Hilferty approximation of the critical
+// value of the chi-square distribution with df degrees of freedom at a
+// significance level of 0.0001; 3.719 is the 0.9999 quantile of the standard
+// normal distribution.
+func chiSquareCritical(df int) float64 {
+	k := 2 / (9 * float64(df))
+	return float64(df) * stdmath.Pow(1-k+3.719*stdmath.Sqrt(k), 3)
+}
+
+func TestFastrandnWideBounds(t *testing.T) {
+	var (
+		bound = uint64(1<<63 + 1<<62)
+		high  int
+	)
+
+	// Bounds are no longer clamped to 32 bits, so roughly one third of these
+	// values should be at least 1<<63.
+	for i := 0; i < 1000; i++ {
+		n := math.Fastrandn(bound)
+		require.Less(t, n, bound)
+		if n >= 1<<63 {
+			high++
+		}
+	}
+
+	require.InDelta(t, 333, high, 100)
+	require.Less(t, math.Fastrandn(int64(stdmath.MaxInt64)), int64(stdmath.MaxInt64))
+	require.Less(t, math.Fastrandn(1e300), 1e300)
+	require.Less(t, math.Fastrandn(2.5), 2.5)
+	require.Zero(t, math.Fastrandn(0))
+	require.Zero(t, math.Fastrandn(-10))
+	require.Zero(t, math.Fastrandn(stdmath.NaN()))
+}
+
+func requireOK[T any](t *testing.T, want T) func(T, bool) {
+	return func(give T, ok bool) {
+		t.Helper()
+		require.True(t, ok)
+		require.Equal(t, want, give)
+	}
+}
+
+func requireNotOK[T any](t *testing.T) func(T, bool) {
+	return func(_ T, ok bool) {
+		t.Helper()
+		require.False(t, ok)
+	}
+}
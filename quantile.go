@@ -0,0 +1,260 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// QuantileMethod selects one of the nine sample quantile definitions described
+// by Hyndman and Fan (1996), numbered as they are in R's quantile function.
+type QuantileMethod int
+
+const (
+	// QuantileR1 is the inverse of the empirical distribution function, and is
+	// equivalent to the nearest-rank method.
+	QuantileR1 QuantileMethod = iota + 1
+	// QuantileR2 is like QuantileR1, but averages at discontinuities.
+	QuantileR2
+	// QuantileR3 selects the observation whose rank is nearest to n*q,
+	// preferring even ranks in the case of a tie.
+	QuantileR3
+	// QuantileR4 linearly interpolates the empirical distribution function.
+	QuantileR4
+	// QuantileR5 linearly interpolates between the midpoints of the steps of
+	// the empirical distribution function.
+	QuantileR5
+	// QuantileR6 linearly interpolates with p[k] = k/(n+1).
+	QuantileR6
+	// QuantileR7 linearly interpolates with p[k] = (k-1)/(n-1). This is the
+	// default method used by R, NumPy, and spreadsheet PERCENTILE functions.
+	QuantileR7
+	// QuantileR8 linearly interpolates such that the result is approximately
+	// median-unbiased regardless of the distribution.
+	QuantileR8
+	// QuantileR9 linearly interpolates such that the result is approximately
+	// unbiased if the distribution is normal.
+	QuantileR9
+
+	// QuantileNearestRank is the nearest-rank method, which is equivalent to
+	// QuantileR1.
+	QuantileNearestRank = QuantileR1
+	// DefaultQuantileMethod is the method used by Quantile, Quantiles, and
+	// Median.
+	DefaultQuantileMethod = QuantileR7
+)
+
+// Median returns the median of the given numbers, or NaN if no numbers are
+// given. The given numbers are not modified.
+func Median[T Numeric](x ...T) float64 {
+	return Quantile(x, 0.5)
+}
+
+// MedianInPlace is like Median, but reorders x instead of allocating.
+func MedianInPlace[T Numeric](x ...T) float64 {
+	return QuantileInPlace(x, 0.5, DefaultQuantileMethod)
+}
+
+// Quantile returns the q-th quantile of x, where q is in [0,1], using
+// DefaultQuantileMethod. NaN is returned if x is empty or q is outside of
+// [0,1]. x is not modified.
+func Quantile[T Numeric](x []T, q float64) float64 {
+	return QuantileWith(x, q, DefaultQuantileMethod)
+}
+
+// QuantileWith is like Quantile, but uses the given method.
+func QuantileWith[T Numeric](x []T, q float64, method QuantileMethod) float64 {
+	return QuantileInPlace(slices.Clone(x), q, method)
+}
+
+// QuantileInPlace is like QuantileWith, but reorders x using quickselect
+// instead of allocating. The resulting order of x is unspecified.
+func QuantileInPlace[T Numeric](x []T, q float64, method QuantileMethod) float64 {
+	idx, frac, ok := quantileIndex(len(x), q, method)
+	if !ok {
+		return math.NaN()
+	}
+
+	selectNth(x, idx)
+	if frac == 0 {
+		return float64(x[idx])
+	}
+
+	// Everything after idx is at least x[idx] once selected, so the next
+	// order statistic is the minimum of the remainder.
+	return lerp(float64(x[idx]), float64(MinN(x[idx+1:]...)), frac)
+}
+
+// Quantiles returns the quantiles of x for each of the given qs using
+// DefaultQuantileMethod. x is not modified.
+func Quantiles[T Numeric](x []T, qs ...float64) []float64 {
+	return QuantilesWith(x, DefaultQuantileMethod, qs...)
+}
+
+// QuantilesWith is like Quantiles, but uses the given method.
+func QuantilesWith[T Numeric](
+	x []T,
+	method QuantileMethod,
+	qs ...float64,
+) []float64 {
+	var (
+		sorted = slices.Clone(x)
+		values = make([]float64, len(qs))
+	)
+
+	slices.Sort(sorted)
+
+	for i, q := range qs {
+		idx, frac, ok := quantileIndex(len(sorted), q, method)
+		switch {
+		case !ok:
+			values[i] = math.NaN()
+		case frac == 0:
+			values[i] = float64(sorted[idx])
+		default:
+			values[i] = lerp(float64(sorted[idx]), float64(sorted[idx+1]), frac)
+		}
+	}
+
+	return values
+}
+
+// quantileIndex returns the zero-based index of the order statistic at or
+// below the q-th quantile of n observations, and the fraction of the distance
+// to the next order statistic at which the quantile lies. The fraction is
+// always zero if the index is the last order statistic.
+//
+//nolint:gocyclo
+func quantileIndex(n int, q float64, method QuantileMethod) (int, float64, bool) {
+	if n == 0 || !(q >= 0 && q <= 1) {
+		return 0, 0, false
+	}
+
+	var (
+		size = float64(n)
+		j    float64 // one-based index
+		g    float64 // fractional part
+	)
+
+	switch method {
+	case QuantileR1, QuantileR2:
+		j, g = math.Modf(size * q)
+		switch {
+		case g > 0:
+			g = 1
+		case method == QuantileR2:
+			g = 0.5
+		}
+	case QuantileR3:
+		j, g = math.Modf(size*q - 0.5)
+		if g < 0 {
+			j, g = j-1, g+1
+		}
+		if g > 0 || math.Mod(j, 2) != 0 {
+			g = 1
+		}
+	case QuantileR4:
+		j, g = math.Modf(size * q)
+	case QuantileR5:
+		j, g = math.Modf(size*q + 0.5)
+	case QuantileR6:
+		j, g = math.Modf((size + 1) * q)
+	case QuantileR7:
+		j, g = math.Modf((size-1)*q + 1)
+	case QuantileR8:
+		j, g = math.Modf((size+1.0/3.0)*q + 1.0/3.0)
+	case QuantileR9:
+		j, g = math.Modf((size+0.25)*q + 0.375)
+	default:
+		return 0, 0, false
+	}
+
+	if g == 1 {
+		j, g = j+1, 0
+	}
+
+	switch {
+	case j < 1:
+		return 0, 0, true
+	case j >= size:
+		return n - 1, 0, true
+	default:
+		return int(j) - 1, g, true
+	}
+}
+
+// selectNth partially sorts x such that x[k] is the element that would be at
+// index k if x were sorted, everything before it is less than or equal to it,
+// and everything after it is greater than or equal to it.
+//
+//nolint:gocyclo
+func selectNth[T Numeric](x []T, k int) {
+	lo, hi := 0, len(x)-1
+
+	for lo < hi {
+		// Use the median of three as the pivot, which also avoids quadratic
+		// behavior for inputs that are already sorted.
+		mid := lo + (hi-lo)/2
+		if x[mid] < x[lo] {
+			x[mid], x[lo] = x[lo], x[mid]
+		}
+		if x[hi] < x[lo] {
+			x[hi], x[lo] = x[lo], x[hi]
+		}
+		if x[hi] < x[mid] {
+			x[hi], x[mid] = x[mid], x[hi]
+		}
+
+		var (
+			pivot = x[mid]
+			i, j  = lo, hi
+		)
+
+		for i <= j {
+			for x[i] < pivot {
+				i++
+			}
+			for x[j] > pivot {
+				j--
+			}
+			if i <= j {
+				x[i], x[j] = x[j], x[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+func lerp(a float64, b float64, frac float64) float64 {
+	return a + (b-a)*frac
+}
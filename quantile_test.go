@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"fmt"
+	stdmath "math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestMedian(t *testing.T) {
+	require.Equal(t, 3.0, math.Median(3, 1, 5))
+	require.Equal(t, 3.5, math.Median(3, 1, 4, 1, 5, 9, 2, 6))
+	require.Equal(t, 3.5, math.Median[int8](3, 1, 4, 1, 5, 9, 2, 6))
+	require.Equal(t, 3.5, math.Median[uint64](3, 1, 4, 1, 5, 9, 2, 6))
+	require.Equal(t, 3.5, math.Median[time.Duration](3, 1, 4, 1, 5, 9, 2, 6))
+	require.Equal(t, 3.5, math.Median[float32](3, 1, 4, 1, 5, 9, 2, 6))
+	require.Equal(t, 7.0, math.Median(7))
+	require.True(t, stdmath.IsNaN(math.Median[int]()))
+
+	x := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	require.Equal(t, 3.5, math.Median(x...))
+	require.Equal(t, []int{3, 1, 4, 1, 5, 9, 2, 6}, x)
+	require.Equal(t, 3.5, math.MedianInPlace(x...))
+	require.ElementsMatch(t, []int{3, 1, 4, 1, 5, 9, 2, 6}, x)
+}
+
+func TestQuantileMethods(t *testing.T) {
+	var (
+		give = []int{3, 1, 4, 1, 5, 9, 2, 6}
+		qs   = []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 1}
+		want = map[math.QuantileMethod][]float64{
+			math.QuantileR1: {1, 1, 1, 3, 5, 9, 9},
+			math.QuantileR2: {1, 1, 1.5, 3.5, 5.5, 9, 9},
+			math.QuantileR3: {1, 1, 1, 3, 5, 6, 9},
+			math.QuantileR4: {1, 1, 1, 3, 5, 6.6, 9},
+			math.QuantileR5: {1, 1, 1.5, 3.5, 5.5, 8.1, 9},
+			math.QuantileR6: {1, 1, 1.25, 3.5, 5.75, 9, 9},
+			math.QuantileR7: {1, 1, 1.75, 3.5, 5.25, 6.9, 9},
+			math.QuantileR8: {1, 1, 17.0 / 12.0, 3.5, 67.0 / 12.0, 8.5, 9},
+			math.QuantileR9: {1, 1, 1.4375, 3.5, 5.5625, 8.4, 9},
+		}
+	)
+
+	for method, values := range want {
+		t.Run(fmt.Sprintf("R%d", method), func(t *testing.T) {
+			require.InDeltaSlice(t, values, math.QuantilesWith(give, method, qs...), 1e-12)
+
+			for i, q := range qs {
+				require.InDelta(t, values[i], math.QuantileWith(give, q, method), 1e-12)
+
+				x := append([]int(nil), give...)
+				require.InDelta(t, values[i], math.QuantileInPlace(x, q, method), 1e-12)
+			}
+		})
+	}
+
+	require.Equal(t, math.QuantileR1, math.QuantileNearestRank)
+	require.Equal(t, math.QuantileR7, math.DefaultQuantileMethod)
+}
+
+func TestQuantile(t *testing.T) {
+	x := []float64{3, 1, 4, 1, 5, 9, 2, 6}
+	require.Equal(t, 1.75, math.Quantile(x, 0.25))
+	require.Equal(t, []float64{3, 1, 4, 1, 5, 9, 2, 6}, x)
+	require.Equal(t, []float64{1, 1.75, 3.5, 5.25, 9}, math.Quantiles(x, 0, 0.25, 0.5, 0.75, 1))
+	require.Equal(t, []float64{3, 1, 4, 1, 5, 9, 2, 6}, x)
+
+	require.True(t, stdmath.IsNaN(math.Quantile(x, -0.1)))
+	require.True(t, stdmath.IsNaN(math.Quantile(x, 1.1)))
+	require.True(t, stdmath.IsNaN(math.Quantile(x, stdmath.NaN())))
+	require.True(t, stdmath.IsNaN(math.Quantile([]float64{}, 0.5)))
+	require.True(t, stdmath.IsNaN(math.QuantileWith(x, 0.5, math.QuantileMethod(0))))
+	require.True(t, stdmath.IsNaN(math.Quantiles(x, 2)[0]))
+	require.Empty(t, math.Quantiles(x))
+}
+
+func TestQuantileInPlace(t *testing.T) {
+	// Exercise quickselect against sorting with many duplicates and sizes.
+	for size := 1; size <= 64; size++ {
+		var (
+			give   = make([]int, size)
+			sorted = make([]int, size)
+		)
+
+		for i := range give {
+			give[i] = (i * 7919) % (size/2 + 1)
+		}
+
+		copy(sorted, give)
+		sort.Ints(sorted)
+
+		for k := 0; k <= 20; k++ {
+			var (
+				q    = float64(k) / 20
+				x    = append([]int(nil), give...)
+				rank = math.ClampMin(int(stdmath.Ceil(q*float64(size))), 1)
+				want = float64(sorted[rank-1])
+			)
+
+			require.Equal(t, want, math.QuantileInPlace(x, q, math.QuantileR1))
+			require.ElementsMatch(t, give, x)
+		}
+	}
+}
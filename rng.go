@@ -0,0 +1,171 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Source is a source of uniformly distributed pseudorandom uint64 values.
+type Source interface {
+	Uint64() uint64
+}
+
+// FastSource is a Source backed by the global math/rand/v2 generator, the same
+// generator used by Fastrand. It is safe for concurrent use, but cannot be
+// seeded; use an RNG where reproducibility is required.
+var FastSource Source = fastSource{}
+
+type fastSource struct{}
+
+func (fastSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// RNGState is the internal state of an RNG.
+type RNGState [4]uint64
+
+// RNG is a seedable xoshiro256** pseudorandom number generator. Its zero value
+// is equivalent to an RNG created with NewRNG(0). RNG is not safe for
+// concurrent use.
+type RNG struct {
+	state RNGState
+}
+
+var _ Source = (*RNG)(nil)
+
+// NewRNG returns a new RNG seeded with the given seed.
+func NewRNG(seed uint64) *RNG {
+	r := &RNG{}
+	r.Seed(seed)
+	return r
+}
+
+// Seed resets r to the state derived from the given seed. RNGs with the same
+// seed produce the same sequence of values.
+func (r *RNG) Seed(seed uint64) {
+	// Expand the seed with SplitMix64, as recommended by the authors of
+	// xoshiro, so that similar seeds produce unrelated states.
+	for i := range r.state {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		r.state[i] = z ^ (z >> 31)
+	}
+}
+
+// State returns the current state of r, which can later be restored with
+// SetState to replay the values produced after this call.
+func (r *RNG) State() RNGState {
+	r.init()
+	return r.state
+}
+
+// SetState restores a state previously returned by State. The all-zero state
+// is not a valid xoshiro state, and is treated like the zero value of RNG.
+func (r *RNG) SetState(state RNGState) {
+	r.state = state
+}
+
+// Uint64 returns a pseudorandom uint64.
+func (r *RNG) Uint64() uint64 {
+	r.init()
+
+	s := &r.state
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+
+	return result
+}
+
+func (r *RNG) init() {
+	if r.state == (RNGState{}) {
+		r.Seed(0)
+	}
+}
+
+// Uint returns a pseudorandom T from src, uniformly distributed over all
+// values of T.
+func Uint[T constraints.Unsigned](src Source) T {
+	return T(src.Uint64())
+}
+
+// Intn returns a pseudorandom T from src in the range [0, n). If n is not
+// positive, 0 is returned.
+func Intn[T constraints.Integer](src Source, n T) T {
+	if n <= 0 {
+		return 0
+	}
+
+	// Lemire's nearly divisionless method: the high word of x*n is uniform in
+	// [0, n) once the biased low words are rejected.
+	var (
+		bound  = uint64(n)
+		hi, lo = bits.Mul64(src.Uint64(), bound)
+	)
+
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(src.Uint64(), bound)
+		}
+	}
+
+	return T(hi)
+}
+
+// IntRange returns a pseudorandom T from src in the inclusive range [lo, hi].
+// If hi is less than lo, the bounds are swapped.
+func IntRange[T constraints.Integer](src Source, lo T, hi T) T {
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+
+	// Converting to uint64 sign-extends signed values, so the difference is
+	// the width of the range even when it spans zero.
+	span := uint64(hi) - uint64(lo)
+	if span == math.MaxUint64 {
+		return T(src.Uint64())
+	}
+
+	return T(uint64(lo) + Intn(src, span+1))
+}
+
+// Float returns a pseudorandom T from src in the range [0, 1).
+func Float[T constraints.Float](src Source) T {
+	if BitSize[T]() == 32 {
+		return T(float32(src.Uint64()>>40) / (1 << 24))
+	}
+
+	return T(float64(src.Uint64()>>11) / (1 << 53))
+}
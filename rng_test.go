@@ -0,0 +1,194 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestRNGReferenceValues(t *testing.T) {
+	// The first outputs of SplitMix64 seeded with 0.
+	require.Equal(
+		t,
+		math.RNGState{
+			0xe220a8397b1dcdaf,
+			0x6e789e6aa1b965f4,
+			0x06c45d188009454f,
+			0xf88bb8a8724c81ec,
+		},
+		math.NewRNG(0).State(),
+	)
+
+	// The first outputs of the reference xoshiro256** implementation from
+	// the state {1, 2, 3, 4}.
+	var rng math.RNG
+	rng.SetState(math.RNGState{1, 2, 3, 4})
+
+	for _, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		require.Equal(t, want, rng.Uint64())
+	}
+}
+
+func TestRNGReproducible(t *testing.T) {
+	var (
+		a    = math.NewRNG(42)
+		b    = math.NewRNG(42)
+		c    = math.NewRNG(43)
+		same = true
+	)
+
+	for i := 0; i < 100; i++ {
+		x := a.Uint64()
+		require.Equal(t, x, b.Uint64())
+		same = same && x == c.Uint64()
+	}
+
+	require.False(t, same)
+
+	a.Seed(42)
+	b.Seed(42)
+	require.Equal(t, a.State(), b.State())
+	require.Equal(t, a.Uint64(), b.Uint64())
+}
+
+func TestRNGZeroValue(t *testing.T) {
+	var (
+		zero math.RNG
+		rng  = math.NewRNG(0)
+	)
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, rng.Uint64(), zero.Uint64())
+	}
+
+	zero.SetState(math.RNGState{})
+	rng.Seed(0)
+	require.Equal(t, rng.Uint64(), zero.Uint64())
+}
+
+func TestRNGState(t *testing.T) {
+	rng := math.NewRNG(uint64(time.Now().UnixNano()))
+	rng.Uint64()
+
+	var (
+		state = rng.State()
+		want  = make([]uint64, 10)
+	)
+
+	for i := range want {
+		want[i] = rng.Uint64()
+	}
+
+	other := math.NewRNG(0)
+	other.SetState(state)
+	for i := range want {
+		require.Equal(t, want[i], other.Uint64())
+	}
+
+	rng.SetState(state)
+	for i := range want {
+		require.Equal(t, want[i], rng.Uint64())
+	}
+}
+
+func TestUint(t *testing.T) {
+	rng := math.NewRNG(1)
+
+	var seen [256]bool
+	for i := 0; i < 10000; i++ {
+		seen[math.Uint[uint8](rng)] = true
+	}
+
+	for i, ok := range seen {
+		require.True(t, ok, i)
+	}
+
+	require.NotEqual(t, math.Uint[uint64](rng), math.Uint[uint64](rng))
+	require.NotEqual(t, math.Uint[uint64](math.FastSource), math.Uint[uint64](math.FastSource))
+}
+
+func TestIntn(t *testing.T) {
+	for _, src := range []math.Source{math.NewRNG(1), math.FastSource} {
+		require.Equal(t, 0, math.Intn(src, 0))
+		require.Equal(t, 0, math.Intn(src, -5))
+		require.Equal(t, int8(0), math.Intn[int8](src, 1))
+
+		const (
+			n     = 10
+			draws = 100000
+		)
+
+		var counts [n]int
+		for i := 0; i < draws; i++ {
+			x := math.Intn(src, n)
+			require.True(t, x >= 0 && x < n, x)
+			counts[x]++
+		}
+
+		// Chi-square with 9 degrees of freedom; the 99.99th percentile is
+		// about 33.7.
+		var chi2 float64
+		for _, c := range counts {
+			d := float64(c) - draws/n
+			chi2 += d * d / (draws / n)
+		}
+		require.Less(t, chi2, 33.7)
+
+		// Bounds that are not powers of 2 and near the top of the range
+		// exercise rejection.
+		for i := 0; i < 1000; i++ {
+			require.Less(t, math.Intn[uint64](src, 1<<63+1), uint64(1<<63+1))
+			require.Less(t, math.Intn[int32](src, 3), int32(3))
+			require.Less(t, math.Intn(src, time.Second), time.Second)
+		}
+	}
+}
+
+func TestFloat(t *testing.T) {
+	for _, src := range []math.Source{math.NewRNG(1), math.FastSource} {
+		var sum float64
+		for i := 0; i < 100000; i++ {
+			x := math.Float[float64](src)
+			require.True(t, x >= 0 && x < 1, x)
+			sum += x
+
+			y := math.Float[float32](src)
+			require.True(t, y >= 0 && y < 1, y)
+		}
+
+		require.InDelta(t, 0.5, sum/100000, 0.01)
+	}
+
+	require.Equal(t, 0.0, math.Float[float64](constSource(0)))
+	require.Equal(t, float32(0), math.Float[float32](constSource(0)))
+	require.Less(t, math.Float[float64](constSource(^uint64(0))), 1.0)
+	require.Less(t, math.Float[float32](constSource(^uint64(0))), float32(1))
+}
+
+type constSource uint64
+
+func (s constSource) Uint64() uint64 {
+	return uint64(s)
+}
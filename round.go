@@ -0,0 +1,331 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// RoundingMode selects how a value is rounded when it cannot be represented
+// exactly at the requested precision.
+type RoundingMode int
+
+const (
+	// RoundHalfAwayFromZero rounds to the nearest value, and rounds ties away
+	// from zero. This is the behavior of math.Round.
+	RoundHalfAwayFromZero RoundingMode = iota + 1
+	// RoundHalfEven rounds to the nearest value, and rounds ties to the
+	// nearest even digit. This is also known as banker's rounding, and is the
+	// behavior of math.RoundToEven.
+	RoundHalfEven
+	// RoundHalfUp rounds to the nearest value, and rounds ties toward positive
+	// infinity.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest value, and rounds ties toward
+	// negative infinity.
+	RoundHalfDown
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeil rounds toward positive infinity.
+	RoundCeil
+	// RoundTruncate rounds toward zero.
+	RoundTruncate
+
+	// DefaultRoundingMode is the mode used by Precision.
+	DefaultRoundingMode = RoundHalfAwayFromZero
+)
+
+// RoundTo rounds x to a multiple of m using the given rounding mode, e.g.
+// RoundTo(1500*time.Millisecond, time.Second, RoundHalfEven) is 2s. The
+// returned bool is false if m is not positive, if mode is invalid, if x or m
+// is not finite, or if the result is not representable by T.
+//
+// For floating point types, x and m are treated as the binary values they
+// hold, so e.g. FloorTo(0.3, 0.1) is 0.2 because 0.1 is slightly larger than
+// one tenth. Use PrecisionMode to round to decimal places.
+func RoundTo[T Numeric](x T, m T, mode RoundingMode) (T, bool) {
+	if m <= 0 || !mode.valid() || !isFinite(x) || !isFinite(m) {
+		return 0, false
+	}
+
+	below, lowerOdd := floorRemainder(x, m)
+	if below == 0 {
+		return x, true
+	}
+
+	// Map the problem onto rounding a magnitude: the remainder is the distance
+	// from x toward zero, and the truncated multiple is the adjacent multiple
+	// in that direction.
+	var (
+		neg   = x < 0
+		above = m - below
+		order = compare(below, above)
+	)
+
+	if neg {
+		order = -order
+	}
+
+	// Rounding away from zero means rounding up for positive x and down for
+	// negative x.
+	if mode.roundUp(neg, lowerOdd != neg, order) == neg {
+		return SubChecked(x, below)
+	}
+
+	if IsFloat[T]() {
+		// m-below may have been rounded, so step up from the lower multiple
+		// instead.
+		return AddChecked(x-below, m)
+	}
+
+	return AddChecked(x, above)
+}
+
+// FloorTo rounds x down to a multiple of m, e.g. FloorTo(1500*time.Millisecond,
+// time.Second) is 1s and FloorTo(-1500*time.Millisecond, time.Second) is -2s.
+// See RoundTo for details.
+func FloorTo[T Numeric](x T, m T) (T, bool) {
+	return RoundTo(x, m, RoundFloor)
+}
+
+// CeilTo rounds x up to a multiple of m, e.g. CeilTo(5000, 4096) is 8192. See
+// RoundTo for details.
+func CeilTo[T Numeric](x T, m T) (T, bool) {
+	return RoundTo(x, m, RoundCeil)
+}
+
+// floorRemainder returns the distance from x down to the nearest multiple of m
+// that is not greater than x, which is in [0, m), and whether that multiple is
+// an odd multiple of m.
+func floorRemainder[T Numeric](x T, m T) (T, bool) {
+	if IsFloat[T]() {
+		return floorRemainderFloat(x, m)
+	}
+
+	return floorRemainderInt(x, m)
+}
+
+func floorRemainderFloat[T Numeric](x T, m T) (T, bool) {
+	below := T(math.Mod(float64(x), float64(m)))
+	if below < 0 {
+		below += m
+	}
+
+	q := math.Round(float64(x-below) / float64(m))
+	return below, math.Mod(q, 2) != 0
+}
+
+func floorRemainderInt[T Numeric](x T, m T) (T, bool) {
+	var (
+		q     = x / m
+		below = x - q*m
+	)
+
+	if below < 0 {
+		below += m
+		q--
+	}
+
+	return below, q-q/2*2 != 0
+}
+
+// compare returns -1, 0, or 1 if x is less than, equal to, or greater than y.
+func compare[T Numeric](x T, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m RoundingMode) valid() bool {
+	return m >= RoundHalfAwayFromZero && m <= RoundTruncate
+}
+
+// roundUp reports whether a value should be rounded away from zero, given
+// its sign, whether the last digit kept is odd, and how the discarded
+// remainder compares to half of a unit in the last place kept (cmp is -1, 0,
+// or 1). The remainder is never zero.
+//
+//nolint:gocyclo
+func (m RoundingMode) roundUp(neg bool, odd bool, cmp int) bool {
+	switch m {
+	case RoundHalfAwayFromZero:
+		return cmp >= 0
+	case RoundHalfEven:
+		return cmp > 0 || (cmp == 0 && odd)
+	case RoundHalfUp:
+		return cmp > 0 || (cmp == 0 && !neg)
+	case RoundHalfDown:
+		return cmp > 0 || (cmp == 0 && neg)
+	case RoundFloor:
+		return neg
+	case RoundCeil:
+		return !neg
+	default:
+		return false
+	}
+}
+
+// decimal is the shortest decimal representation of a finite float that
+// round-trips, with value digits*10^exp (negated if neg). Rounding is
+// performed on the digits themselves, so that values like 1.005 behave as
+// written rather than as their nearest binary approximation.
+type decimal struct {
+	digits []byte
+	exp    int
+	neg    bool
+}
+
+func newDecimal(x float64, bitSize int) decimal {
+	// The 'e' format is always of the form [-]d[.ddd]e±dd.
+	var (
+		buf = strconv.AppendFloat(nil, x, 'e', -1, bitSize)
+		d   decimal
+	)
+
+	if buf[0] == '-' {
+		d.neg = true
+		buf = buf[1:]
+	}
+
+	var (
+		e   int
+		err error
+	)
+
+	for i, c := range buf {
+		if c == 'e' {
+			e, err = strconv.Atoi(string(buf[i+1:]))
+			buf = buf[:i]
+			break
+		}
+	}
+
+	if err != nil {
+		// The exponent written by AppendFloat is always a valid integer.
+		panic(err)
+	}
+
+	d.digits = make([]byte, 0, len(buf))
+	for _, c := range buf {
+		if c != '.' {
+			d.digits = append(d.digits, c)
+		}
+	}
+
+	// Trailing zeros only occur when x is zero.
+	for len(d.digits) > 1 && d.digits[len(d.digits)-1] == '0' {
+		d.digits = d.digits[:len(d.digits)-1]
+	}
+
+	d.exp = e - (len(d.digits) - 1)
+	return d
+}
+
+// round rounds d to its first n significant digits using the given mode. n
+// may be zero or negative, in which case d is rounded to a power of 10 larger
+// than its leading digit.
+func (d *decimal) round(n int, mode RoundingMode) {
+	if n >= len(d.digits) || d.isZero() {
+		return
+	}
+
+	var cmp int
+	if n < 0 {
+		// The remainder is less than a tenth of a unit.
+		cmp = -1
+	} else {
+		switch rest := d.digits[n:]; {
+		case rest[0] < '5':
+			cmp = -1
+		case rest[0] > '5' || len(rest) > 1:
+			cmp = 1
+		}
+	}
+
+	var (
+		kept = d.digits[:Max(n, 0)]
+		odd  = len(kept) > 0 && (kept[len(kept)-1]-'0')%2 == 1
+	)
+
+	d.exp += len(d.digits) - n
+	d.digits = kept
+
+	if mode.roundUp(d.neg, odd, cmp) {
+		d.increment()
+	}
+}
+
+// increment adds one to the last digit of d, carrying as needed.
+func (d *decimal) increment() {
+	for i := len(d.digits) - 1; i >= 0; i-- {
+		if d.digits[i] < '9' {
+			d.digits[i]++
+			return
+		}
+		d.digits[i] = '0'
+	}
+
+	d.digits = append([]byte{'1'}, d.digits...)
+}
+
+func (d decimal) isZero() bool {
+	for _, c := range d.digits {
+		if c != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// float returns the nearest float of the given bit size to d. Values too
+// large to be represented become infinite.
+func (d decimal) float(bitSize int) float64 {
+	if len(d.digits) == 0 {
+		if d.neg {
+			return math.Copysign(0, -1)
+		}
+		return 0
+	}
+
+	buf := make([]byte, 0, len(d.digits)+8)
+	if d.neg {
+		buf = append(buf, '-')
+	}
+	buf = append(buf, d.digits...)
+	buf = append(buf, 'e')
+	buf = strconv.AppendInt(buf, int64(d.exp), 10)
+
+	// ParseFloat only fails here for values out of range, in which case it
+	// returns the appropriately signed infinity.
+	x, err := strconv.ParseFloat(string(buf), bitSize)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		panic(err)
+	}
+
+	return x
+}
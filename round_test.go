@@ -0,0 +1,220 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+	"golang.org/x/exp/constraints"
+)
+
+var roundingModes = []math.RoundingMode{
+	math.RoundHalfAwayFromZero,
+	math.RoundHalfEven,
+	math.RoundHalfUp,
+	math.RoundHalfDown,
+	math.RoundFloor,
+	math.RoundCeil,
+	math.RoundTruncate,
+}
+
+func TestRoundTo(t *testing.T) {
+	const (
+		ms = time.Millisecond
+		s  = time.Second
+	)
+
+	requireOK(t, 2*s)(math.RoundTo(1500*ms, s, math.RoundHalfAwayFromZero))
+	requireOK(t, 2*s)(math.RoundTo(1500*ms, s, math.RoundHalfEven))
+	requireOK(t, 2*s)(math.RoundTo(2500*ms, s, math.RoundHalfEven))
+	requireOK(t, 3*s)(math.RoundTo(2500*ms, s, math.RoundHalfAwayFromZero))
+	requireOK(t, 3*s)(math.RoundTo(2500*ms, s, math.RoundHalfUp))
+	requireOK(t, 2*s)(math.RoundTo(2500*ms, s, math.RoundHalfDown))
+	requireOK(t, 3*s)(math.RoundTo(2501*ms, s, math.RoundHalfDown))
+	requireOK(t, 2*s)(math.RoundTo(2499*ms, s, math.RoundHalfUp))
+	requireOK(t, 2*s)(math.RoundTo(2999*ms, s, math.RoundTruncate))
+
+	requireOK(t, -3*s)(math.RoundTo(-2500*ms, s, math.RoundHalfAwayFromZero))
+	requireOK(t, -2*s)(math.RoundTo(-2500*ms, s, math.RoundHalfEven))
+	requireOK(t, -4*s)(math.RoundTo(-3500*ms, s, math.RoundHalfEven))
+	requireOK(t, -2*s)(math.RoundTo(-2500*ms, s, math.RoundHalfUp))
+	requireOK(t, -3*s)(math.RoundTo(-2500*ms, s, math.RoundHalfDown))
+	requireOK(t, -2*s)(math.RoundTo(-2999*ms, s, math.RoundTruncate))
+
+	for _, mode := range roundingModes {
+		requireOK(t, 3*s)(math.RoundTo(3*s, s, mode))
+		requireOK(t, -3*s)(math.RoundTo(-3*s, s, mode))
+		requireOK(t, time.Duration(0))(math.RoundTo(0, s, mode))
+	}
+
+	requireNotOK[time.Duration](t)(math.RoundTo(s, 0, math.RoundFloor))
+	requireNotOK[time.Duration](t)(math.RoundTo(s, -s, math.RoundFloor))
+	requireNotOK[time.Duration](t)(math.RoundTo(s, s, 0))
+	requireNotOK[time.Duration](t)(math.RoundTo(s, s, math.RoundTruncate+1))
+}
+
+func TestFloorToCeilTo(t *testing.T) {
+	const (
+		ms  = time.Millisecond
+		s   = time.Second
+		kib = 1 << 10
+	)
+
+	requireOK(t, s)(math.FloorTo(1500*ms, s))
+	requireOK(t, -2*s)(math.FloorTo(-1500*ms, s))
+	requireOK(t, 2*s)(math.CeilTo(1500*ms, s))
+	requireOK(t, -s)(math.CeilTo(-1500*ms, s))
+
+	requireOK(t, uint64(8*kib))(math.CeilTo[uint64](5000, 4*kib))
+	requireOK(t, uint64(4*kib))(math.CeilTo[uint64](4*kib, 4*kib))
+	requireOK(t, uint64(4*kib))(math.FloorTo[uint64](5000, 4*kib))
+	requireOK(t, uint64(0))(math.FloorTo[uint64](4095, 4*kib))
+	requireOK(t, uint64(4*kib))(math.CeilTo[uint64](1, 4*kib))
+
+	// Overflow at the type bounds.
+	requireOK(t, int8(120))(math.CeilTo[int8](111, 10))
+	requireNotOK[int8](t)(math.CeilTo[int8](121, 10))
+	requireOK(t, int8(-120))(math.FloorTo[int8](-111, 10))
+	requireNotOK[int8](t)(math.FloorTo[int8](-121, 10))
+	requireOK(t, int8(stdmath.MinInt8))(math.FloorTo[int8](stdmath.MinInt8, 2))
+	requireOK(t, int8(stdmath.MaxInt8))(math.CeilTo[int8](1, stdmath.MaxInt8))
+	requireOK(t, uint8(250))(math.FloorTo[uint8](stdmath.MaxUint8, 10))
+	requireNotOK[uint8](t)(math.CeilTo[uint8](stdmath.MaxUint8, 10))
+	requireNotOK[uint8](t)(math.RoundTo[uint8](255, 10, math.RoundHalfAwayFromZero))
+	requireNotOK[int64](t)(math.CeilTo[int64](stdmath.MaxInt64, 3))
+	requireNotOK[int64](t)(math.FloorTo[int64](stdmath.MinInt64, 3))
+	requireOK(t, uint64(stdmath.MaxUint64))(
+		math.FloorTo[uint64](stdmath.MaxUint64, stdmath.MaxUint64),
+	)
+}
+
+func TestRoundToFloat(t *testing.T) {
+	requireOK(t, 7.0)(math.FloorTo(7.3, 0.5))
+	requireOK(t, 7.5)(math.CeilTo(7.3, 0.5))
+	requireOK(t, -7.5)(math.FloorTo(-7.3, 0.5))
+	requireOK(t, -7.0)(math.CeilTo(-7.3, 0.5))
+	requireOK(t, 7.0)(math.RoundTo(7.25, 0.5, math.RoundHalfEven))
+	requireOK(t, 7.5)(math.RoundTo(7.25, 0.5, math.RoundHalfAwayFromZero))
+	requireOK(t, 8.0)(math.RoundTo(7.75, 0.5, math.RoundHalfEven))
+	requireOK(t, -7.0)(math.RoundTo(-7.25, 0.5, math.RoundHalfEven))
+	requireOK(t, -7.0)(math.RoundTo(-7.25, 0.5, math.RoundHalfUp))
+	requireOK(t, -7.5)(math.RoundTo(-7.25, 0.5, math.RoundHalfDown))
+	requireOK(t, -7.0)(math.RoundTo(-7.4, 0.5, math.RoundTruncate))
+	requireOK(t, float32(7.5))(math.CeilTo[float32](7.3, 0.5))
+	requireOK(t, -1.0)(math.FloorTo(-1e-20, 1))
+	requireOK(t, 0.0)(math.FloorTo(1e-300, stdmath.MaxFloat64))
+	requireOK(t, stdmath.MaxFloat64)(math.CeilTo(1e-300, stdmath.MaxFloat64))
+
+	requireNotOK[float64](t)(math.CeilTo(stdmath.MaxFloat64, 1e308))
+	requireNotOK[float32](t)(math.CeilTo(math.MaxValue[float32](), 1e38))
+	requireNotOK[float64](t)(math.FloorTo(stdmath.NaN(), 1))
+	requireNotOK[float64](t)(math.FloorTo(stdmath.Inf(1), 1))
+	requireNotOK[float64](t)(math.FloorTo(1, stdmath.NaN()))
+	requireNotOK[float64](t)(math.FloorTo(1, stdmath.Inf(1)))
+	requireNotOK[float64](t)(math.FloorTo(1.0, 0))
+	requireNotOK[float64](t)(math.FloorTo(1.0, -0.5))
+}
+
+func TestRoundToExhaustive(t *testing.T) {
+	testRoundToExhaustive[int8](t)
+	testRoundToExhaustive[uint8](t)
+}
+
+// testRoundToExhaustive compares RoundTo against a straightforward oracle
+// computed with int64 arithmetic for every x, m, and mode.
+func testRoundToExhaustive[T int8 | uint8](t *testing.T) {
+	var (
+		minVal = int64(math.MinValue[T]())
+		maxVal = int64(math.MaxValue[T]())
+	)
+
+	for _, mode := range roundingModes {
+		for m := int64(1); m <= maxVal; m++ {
+			for x := minVal; x <= maxVal; x++ {
+				want, wantOK := roundToOracle(x, m, mode)
+				wantOK = wantOK && want >= minVal && want <= maxVal
+
+				got, ok := math.RoundTo(T(x), T(m), mode)
+				if ok != wantOK || (ok && int64(got) != want) {
+					require.Equal(t, wantOK, ok, "%d, %d, %d", x, m, mode)
+					require.Equal(t, want, int64(got), "%d, %d, %d", x, m, mode)
+				}
+			}
+		}
+	}
+}
+
+// roundToOracle rounds x to a multiple of m by comparing its distance to the
+// multiples on either side of it.
+//
+//nolint:gocyclo
+func roundToOracle(x, m int64, mode math.RoundingMode) (int64, bool) {
+	lo := floorDiv(x, m) * m
+	if lo == x {
+		return x, true
+	}
+
+	hi := lo + m
+	switch d := 2*(x-lo) - m; {
+	case mode == math.RoundFloor:
+		return lo, true
+	case mode == math.RoundCeil:
+		return hi, true
+	case mode == math.RoundTruncate:
+		return pick(x < 0, hi, lo), true
+	case d < 0:
+		return lo, true
+	case d > 0:
+		return hi, true
+	}
+
+	switch mode {
+	case math.RoundHalfAwayFromZero:
+		return pick(x < 0, lo, hi), true
+	case math.RoundHalfEven:
+		return pick((lo/m)%2 == 0, lo, hi), true
+	case math.RoundHalfUp:
+		return hi, true
+	case math.RoundHalfDown:
+		return lo, true
+	default:
+		return 0, false
+	}
+}
+
+func floorDiv[T constraints.Signed](x T, y T) T {
+	q := x / y
+	if (x%y != 0) && ((x < 0) != (y < 0)) {
+		q--
+	}
+	return q
+}
+
+func pick[T any](cond bool, a T, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// The functions in this file draw from src, or from FastSource if src is nil.
+
+// Shuffle pseudorandomly permutes x in place using the Fisher-Yates algorithm,
+// such that every permutation is equally likely.
+func Shuffle[T any](src Source, x []T) {
+	src = sourceOrFast(src)
+
+	for i := len(x) - 1; i > 0; i-- {
+		j := Intn(src, i+1)
+		x[i], x[j] = x[j], x[i]
+	}
+}
+
+// Permutation returns a pseudorandom permutation of the integers [0, n). If n
+// is not positive, an empty slice is returned.
+func Permutation(src Source, n int) []int {
+	p := make([]int, Max(n, 0))
+	for i := range p {
+		p[i] = i
+	}
+
+	Shuffle(src, p)
+	return p
+}
+
+// SampleWithoutReplacement returns k distinct integers chosen uniformly from
+// [0, n), in random order. k is clamped to [0, n]. When k is small relative to
+// n, the time and memory used are proportional to k rather than n.
+func SampleWithoutReplacement(src Source, n int, k int) []int {
+	src = sourceOrFast(src)
+	k = Clamp(k, 0, Max(n, 0))
+
+	if k > n/4 {
+		p := Permutation(src, n)
+		return p[:k:k]
+	}
+
+	// Run the first k steps of a forward Fisher-Yates shuffle over a virtual
+	// identity array, recording only the positions that have been swapped.
+	var (
+		swapped = make(map[int]int, k)
+		sample  = make([]int, k)
+	)
+
+	at := func(i int) int {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+
+	for i := range sample {
+		j := i + Intn(src, n-i)
+		sample[i] = at(j)
+		swapped[j] = at(i)
+	}
+
+	return sample
+}
+
+// Reservoir maintains a uniform random sample of up to k values from a stream
+// of unknown length, using Algorithm L of Li (1994), which draws a number of
+// random values proportional to k*log(n/k) rather than n. Reservoir is not
+// safe for concurrent use.
+type Reservoir[T any] struct {
+	src    Source
+	k      int
+	count  int
+	next   int
+	logW   float64
+	sample []T
+}
+
+// NewReservoir returns a new Reservoir that samples up to k values using src,
+// or FastSource if src is nil. If k is not positive, no values are retained.
+func NewReservoir[T any](k int, src Source) *Reservoir[T] {
+	return &Reservoir[T]{
+		src:    sourceOrFast(src),
+		k:      Max(k, 0),
+		sample: make([]T, 0, Max(k, 0)),
+	}
+}
+
+// Add offers x to the reservoir.
+func (r *Reservoir[T]) Add(x T) {
+	i := r.count
+	r.count++
+
+	switch {
+	case len(r.sample) < r.k:
+		r.sample = append(r.sample, x)
+		if len(r.sample) == r.k {
+			r.logW = r.logRand()
+			r.skip(i)
+		}
+	case r.k > 0 && i == r.next:
+		r.sample[Intn(r.src, r.k)] = x
+		r.logW += r.logRand()
+		r.skip(i)
+	}
+}
+
+// Sample returns a copy of the values currently in the reservoir, which are a
+// uniform random sample of min(k, Count()) of the values added so far.
+func (r *Reservoir[T]) Sample() []T {
+	return slices.Clone(r.sample)
+}
+
+// Count returns the number of values added to the reservoir.
+func (r *Reservoir[T]) Count() int {
+	return r.count
+}
+
+// Reset removes all values from the reservoir.
+func (r *Reservoir[T]) Reset() {
+	r.count = 0
+	r.next = 0
+	r.logW = 0
+	r.sample = r.sample[:0]
+}
+
+// logRand returns log(u)/k for u uniform in (0, 1), so that W, the largest of
+// k uniform values, can be maintained as exp(logW) without rounding to 1.
+func (r *Reservoir[T]) logRand() float64 {
+	return math.Log(openUniform(r.src)) / float64(r.k)
+}
+
+// skip sets the index of the next value to be added to the sample, given that
+// i was the last.
+func (r *Reservoir[T]) skip(i int) {
+	var (
+		u    = openUniform(r.src)
+		log1 = math.Log(-math.Expm1(r.logW))
+		n    = ConvertSat[int](math.Floor(math.Log(u) / log1))
+	)
+
+	next, ok := AddChecked(i, n)
+	if ok {
+		next, ok = AddChecked(next, 1)
+	}
+	if !ok {
+		next = math.MaxInt
+	}
+
+	r.next = next
+}
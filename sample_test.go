@@ -0,0 +1,212 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+	"golang.org/x/exp/slices"
+)
+
+func TestShuffle(t *testing.T) {
+	var (
+		rng   = math.NewRNG(1)
+		perms = map[[4]string]int{}
+	)
+
+	// Every one of the 24 permutations of 4 elements should be equally likely.
+	testUniform(t, 24, distributionDraws, func() int {
+		x := []string{"a", "b", "c", "d"}
+		math.Shuffle(rng, x)
+
+		key := [4]string{x[0], x[1], x[2], x[3]}
+		if _, ok := perms[key]; !ok {
+			perms[key] = len(perms)
+		}
+		return perms[key]
+	})
+	require.Len(t, perms, 24)
+
+	math.Shuffle[int](rng, nil)
+	math.Shuffle(nil, []int{1})
+
+	x := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	math.Shuffle(nil, x)
+	slices.Sort(x)
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, x)
+}
+
+func TestPermutation(t *testing.T) {
+	rng := math.NewRNG(2)
+
+	for _, n := range []int{1, 2, 10, 1000} {
+		p := math.Permutation(rng, n)
+		require.Len(t, p, n)
+
+		sorted := slices.Clone(p)
+		slices.Sort(sorted)
+		for i, v := range sorted {
+			require.Equal(t, i, v)
+		}
+	}
+
+	// Element 0 should land in every position with equal probability.
+	testUniform(t, 10, distributionDraws, func() int {
+		return slices.Index(math.Permutation(rng, 10), 0)
+	})
+
+	require.Empty(t, math.Permutation(rng, 0))
+	require.Empty(t, math.Permutation(rng, -1))
+}
+
+func TestSampleWithoutReplacement(t *testing.T) {
+	rng := math.NewRNG(3)
+
+	cases := []struct {
+		n, k int
+	}{
+		{10, 3},        // sparse
+		{10, 8},        // dense
+		{10, 10},       // dense
+		{1000000, 5},   // sparse, with n much larger than k
+		{100, 25},      // sparse, at the threshold
+		{100, 26},      // dense, just past the threshold
+		{1 << 62, 100}, // sparse, with n too large to allocate
+	}
+
+	for _, tt := range cases {
+		var (
+			n, k    = tt.n, tt.k
+			buckets = int(math.Min(int64(n), 10))
+			queue   []int
+		)
+
+		testUniform(t, buckets, distributionDraws, func() int {
+			if len(queue) == 0 {
+				queue = math.SampleWithoutReplacement(rng, n, k)
+				require.Len(t, queue, k)
+
+				seen := make(map[int]struct{}, k)
+				for _, v := range queue {
+					require.True(t, v >= 0 && v < n, v)
+					seen[v] = struct{}{}
+				}
+				require.Len(t, seen, k, "duplicate values")
+			}
+
+			v := queue[0]
+			queue = queue[1:]
+			return int(int64(v) / (int64(n) / int64(buckets)))
+		})
+	}
+
+	// The first element of a sample should be uniform too, i.e. the sample
+	// order is random.
+	testUniform(t, 10, distributionDraws, func() int {
+		return math.SampleWithoutReplacement(rng, 10, 3)[0]
+	})
+
+	require.Len(t, math.SampleWithoutReplacement(rng, 5, 10), 5)
+	require.Empty(t, math.SampleWithoutReplacement(rng, 5, -1))
+	require.Empty(t, math.SampleWithoutReplacement(rng, 0, 3))
+	require.Empty(t, math.SampleWithoutReplacement(rng, -5, 3))
+	require.Len(t, math.SampleWithoutReplacement(nil, 100, 3), 3)
+}
+
+func TestReservoir(t *testing.T) {
+	var (
+		rng    = math.NewRNG(4)
+		r      = math.NewReservoir[int](5, rng)
+		counts = make([]int, 100)
+		trials = 2000
+	)
+
+	for i := 0; i < trials; i++ {
+		r.Reset()
+		for x := 0; x < len(counts); x++ {
+			r.Add(x)
+		}
+
+		require.Equal(t, len(counts), r.Count())
+
+		sample := r.Sample()
+		require.Len(t, sample, 5)
+		for _, x := range sample {
+			counts[x]++
+		}
+	}
+
+	// Every value should be retained with equal probability.
+	testUniformCounts(t, counts)
+}
+
+func TestReservoirLongStream(t *testing.T) {
+	var (
+		rng = math.NewRNG(5)
+		r   = math.NewReservoir[int](10, rng)
+		n   = 1000000
+	)
+
+	for x := 0; x < n; x++ {
+		r.Add(x)
+	}
+
+	// The sample should contain distinct values, each of which should be in
+	// the second half of the stream with probability 1/2.
+	sample := r.Sample()
+	slices.Sort(sample)
+	require.Len(t, slices.Compact(slices.Clone(sample)), 10)
+
+	var high int
+	for i := 0; i < 200; i++ {
+		r.Reset()
+		for x := 0; x < 10000; x++ {
+			r.Add(x)
+		}
+		for _, x := range r.Sample() {
+			if x >= 5000 {
+				high++
+			}
+		}
+	}
+	require.InDelta(t, 1000, high, 100)
+}
+
+func TestReservoirShortStream(t *testing.T) {
+	r := math.NewReservoir[string](5, nil)
+	require.Empty(t, r.Sample())
+
+	r.Add("a")
+	r.Add("b")
+	require.Equal(t, []string{"a", "b"}, r.Sample())
+	require.Equal(t, 2, r.Count())
+
+	// The returned sample is a copy.
+	r.Sample()[0] = "z"
+	require.Equal(t, []string{"a", "b"}, r.Sample())
+
+	empty := math.NewReservoir[string](0, nil)
+	empty.Add("a")
+	require.Empty(t, empty.Sample())
+	require.Equal(t, 1, empty.Count())
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "golang.org/x/exp/constraints"
+
+// AddSat returns x+y, saturating at the minimum or maximum value of T instead
+// of overflowing.
+func AddSat[T constraints.Integer](x T, y T) T {
+	if z, ok := AddChecked(x, y); ok {
+		return z
+	}
+
+	if y < 0 {
+		return MinValue[T]()
+	}
+
+	return MaxValue[T]()
+}
+
+// SubSat returns x-y, saturating at the minimum or maximum value of T instead
+// of overflowing.
+func SubSat[T constraints.Integer](x T, y T) T {
+	if z, ok := SubChecked(x, y); ok {
+		return z
+	}
+
+	if y < 0 {
+		return MaxValue[T]()
+	}
+
+	return MinValue[T]()
+}
+
+// MulSat returns x*y, saturating at the minimum or maximum value of T instead
+// of overflowing.
+func MulSat[T constraints.Integer](x T, y T) T {
+	if z, ok := MulChecked(x, y); ok {
+		return z
+	}
+
+	if (x < 0) != (y < 0) {
+		return MinValue[T]()
+	}
+
+	return MaxValue[T]()
+}
+
+// AbsSat returns the absolute value of x, saturating at the maximum value of T
+// if x is the minimum value of T (whose absolute value is not representable).
+func AbsSat[T constraints.Signed](x T) T {
+	if x >= 0 {
+		return x
+	}
+
+	if z, ok := NegChecked(x); ok {
+		return z
+	}
+
+	return MaxValue[T]()
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+	"golang.org/x/exp/constraints"
+)
+
+func TestAddSat(t *testing.T) {
+	testAddSatSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testAddSatSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testAddSatSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testAddSatSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testAddSatSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testAddSatSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testAddSatUnsigned[uint](t, stdmath.MaxUint)
+	testAddSatUnsigned[uint8](t, stdmath.MaxUint8)
+	testAddSatUnsigned[uint16](t, stdmath.MaxUint16)
+	testAddSatUnsigned[uint32](t, stdmath.MaxUint32)
+	testAddSatUnsigned[uint64](t, stdmath.MaxUint64)
+}
+
+func TestSubSat(t *testing.T) {
+	testSubSatSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testSubSatSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testSubSatSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testSubSatSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testSubSatSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testSubSatSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testSubSatUnsigned[uint](t, stdmath.MaxUint)
+	testSubSatUnsigned[uint8](t, stdmath.MaxUint8)
+	testSubSatUnsigned[uint16](t, stdmath.MaxUint16)
+	testSubSatUnsigned[uint32](t, stdmath.MaxUint32)
+	testSubSatUnsigned[uint64](t, stdmath.MaxUint64)
+}
+
+func TestMulSat(t *testing.T) {
+	testMulSatSigned[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testMulSatSigned[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testMulSatSigned[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testMulSatSigned[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testMulSatSigned[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testMulSatSigned[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testMulSatUnsigned[uint](t, stdmath.MaxUint)
+	testMulSatUnsigned[uint8](t, stdmath.MaxUint8)
+	testMulSatUnsigned[uint16](t, stdmath.MaxUint16)
+	testMulSatUnsigned[uint32](t, stdmath.MaxUint32)
+	testMulSatUnsigned[uint64](t, stdmath.MaxUint64)
+}
+
+func TestAbsSat(t *testing.T) {
+	testAbsSat[int](t, stdmath.MinInt, stdmath.MaxInt)
+	testAbsSat[int8](t, stdmath.MinInt8, stdmath.MaxInt8)
+	testAbsSat[int16](t, stdmath.MinInt16, stdmath.MaxInt16)
+	testAbsSat[int32](t, stdmath.MinInt32, stdmath.MaxInt32)
+	testAbsSat[int64](t, stdmath.MinInt64, stdmath.MaxInt64)
+	testAbsSat[time.Duration](t, stdmath.MinInt64, stdmath.MaxInt64)
+}
+
+func testAddSatSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	require.Equal(t, T(3), math.AddSat[T](1, 2))
+	require.Equal(t, T(-3), math.AddSat[T](-1, -2))
+	require.Equal(t, T(-1), math.AddSat(minVal, maxVal))
+	require.Equal(t, maxVal, math.AddSat(maxVal-1, 1))
+	require.Equal(t, maxVal, math.AddSat(maxVal, 1))
+	require.Equal(t, maxVal, math.AddSat(1, maxVal))
+	require.Equal(t, maxVal, math.AddSat(maxVal, maxVal))
+	require.Equal(t, minVal, math.AddSat(minVal+1, -1))
+	require.Equal(t, minVal, math.AddSat(minVal, -1))
+	require.Equal(t, minVal, math.AddSat(-1, minVal))
+	require.Equal(t, minVal, math.AddSat(minVal, minVal))
+}
+
+func testAddSatUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	require.Equal(t, T(3), math.AddSat[T](1, 2))
+	require.Equal(t, maxVal, math.AddSat(maxVal-1, 1))
+	require.Equal(t, maxVal, math.AddSat(maxVal, 1))
+	require.Equal(t, maxVal, math.AddSat(1, maxVal))
+	require.Equal(t, maxVal, math.AddSat(maxVal, maxVal))
+}
+
+func testSubSatSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	require.Equal(t, T(-1), math.SubSat[T](1, 2))
+	require.Equal(t, T(1), math.SubSat[T](-1, -2))
+	require.Equal(t, maxVal, math.SubSat(maxVal-1, -1))
+	require.Equal(t, maxVal, math.SubSat(maxVal, -1))
+	require.Equal(t, maxVal, math.SubSat(0, minVal))
+	require.Equal(t, maxVal, math.SubSat(maxVal, minVal))
+	require.Equal(t, minVal, math.SubSat(minVal+1, 1))
+	require.Equal(t, minVal, math.SubSat(minVal, 1))
+	require.Equal(t, minVal, math.SubSat(minVal, maxVal))
+}
+
+func testSubSatUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	require.Equal(t, T(1), math.SubSat[T](2, 1))
+	require.Equal(t, maxVal-1, math.SubSat(maxVal, 1))
+	require.Equal(t, T(0), math.SubSat[T](1, 2))
+	require.Equal(t, T(0), math.SubSat(0, maxVal))
+	require.Equal(t, T(0), math.SubSat(maxVal-1, maxVal))
+}
+
+func testMulSatSigned[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	require.Equal(t, T(6), math.MulSat[T](2, 3))
+	require.Equal(t, T(-6), math.MulSat[T](-2, 3))
+	require.Equal(t, T(6), math.MulSat[T](-2, -3))
+	require.Equal(t, T(0), math.MulSat(minVal, 0))
+	require.Equal(t, -maxVal, math.MulSat(maxVal, -1))
+	require.Equal(t, maxVal, math.MulSat(minVal, -1))
+	require.Equal(t, maxVal, math.MulSat(-1, minVal))
+	require.Equal(t, maxVal, math.MulSat(maxVal, 2))
+	require.Equal(t, maxVal, math.MulSat(minVal, minVal))
+	require.Equal(t, maxVal, math.MulSat(maxVal, maxVal))
+	require.Equal(t, minVal, math.MulSat(minVal, 2))
+	require.Equal(t, minVal, math.MulSat(maxVal, -2))
+	require.Equal(t, minVal, math.MulSat(-2, maxVal))
+	require.Equal(t, minVal, math.MulSat(minVal, maxVal))
+}
+
+func testMulSatUnsigned[T constraints.Unsigned](t *testing.T, maxVal T) {
+	require.Equal(t, T(6), math.MulSat[T](2, 3))
+	require.Equal(t, T(0), math.MulSat(maxVal, 0))
+	require.Equal(t, maxVal-1, math.MulSat(maxVal/2, 2))
+	require.Equal(t, maxVal, math.MulSat(maxVal/2+1, 2))
+	require.Equal(t, maxVal, math.MulSat(maxVal, maxVal))
+}
+
+func testAbsSat[T constraints.Signed](t *testing.T, minVal T, maxVal T) {
+	require.Equal(t, T(0), math.AbsSat[T](0))
+	require.Equal(t, T(10), math.AbsSat[T](10))
+	require.Equal(t, T(10), math.AbsSat[T](-10))
+	require.Equal(t, maxVal, math.AbsSat(maxVal))
+	require.Equal(t, maxVal, math.AbsSat(-maxVal))
+	require.Equal(t, maxVal, math.AbsSat(minVal))
+}
@@ -0,0 +1,467 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// DefaultSketchSize is the accuracy parameter used by a zero-value Sketch.
+	DefaultSketchSize = 200
+
+	sketchVersion = 1
+	sketchDecay   = 2.0 / 3.0
+)
+
+// ErrInvalidSketch indicates that a Sketch could not be decoded.
+var ErrInvalidSketch = errors.New("math: invalid sketch encoding")
+
+// Sketch is an approximate quantile sketch based on the KLL algorithm of
+// Karnin, Lang, and Liberty (2016). Rather than retaining every number added
+// to it, a Sketch retains O(k*log(n/k)) numbers, where k is its size, and
+// answers quantile and rank queries with an error (as a fraction of n) that is
+// roughly proportional to 1/k; a size of 200 keeps the error to within about
+// 1%. The minimum and maximum values are always exact.
+//
+// The zero value is ready to use with DefaultSketchSize and FastSource. Sketch
+// is not safe for concurrent use; each goroutine should use its own Sketch,
+// combining them with Merge.
+type Sketch[T Numeric] struct {
+	src     Source
+	k       int
+	count   int
+	size    int
+	maxSize int
+	min     T
+	max     T
+	levels  [][]T
+}
+
+// NewSketch returns a new Sketch with the given size, which trades memory for
+// accuracy, that randomizes compactions using src, or FastSource if src is
+// nil. If k is not positive, DefaultSketchSize is used.
+func NewSketch[T Numeric](k int, src Source) *Sketch[T] {
+	s := &Sketch[T]{src: src}
+	s.init(k)
+	return s
+}
+
+// Add adds x to the sketch.
+func (s *Sketch[T]) Add(x T) {
+	if s.levels == nil {
+		s.init(s.k)
+	}
+
+	if s.count == 0 {
+		s.min, s.max = x, x
+	} else {
+		s.min = Min(s.min, x)
+		s.max = Max(s.max, x)
+	}
+
+	s.levels[0] = append(s.levels[0], x)
+	s.count++
+	s.size++
+
+	if s.size >= s.maxSize {
+		s.compress()
+	}
+}
+
+// Merge adds all numbers summarized by other to s.
+func (s *Sketch[T]) Merge(other *Sketch[T]) {
+	if other.count == 0 {
+		return
+	}
+
+	if s.levels == nil {
+		s.init(s.k)
+	}
+
+	if s.count == 0 {
+		s.min, s.max = other.min, other.max
+	} else {
+		s.min = Min(s.min, other.min)
+		s.max = Max(s.max, other.max)
+	}
+
+	for len(s.levels) < len(other.levels) {
+		s.grow()
+	}
+
+	// Capture other's levels first, in case other is s.
+	levels := other.levels
+	for h := range levels {
+		s.levels[h] = append(s.levels[h], levels[h]...)
+	}
+
+	s.count += other.count
+	s.size = 0
+	for _, level := range s.levels {
+		s.size += len(level)
+	}
+
+	for s.size >= s.maxSize {
+		s.compress()
+	}
+}
+
+// Reset clears all numbers that have been added to the sketch.
+func (s *Sketch[T]) Reset() {
+	s.init(s.k)
+}
+
+// Count returns the number of numbers that have been added to the sketch.
+func (s *Sketch[T]) Count() int {
+	return s.count
+}
+
+// Min returns the minimum number that has been added to the sketch, or 0 if no
+// numbers have been added.
+func (s *Sketch[T]) Min() T {
+	return s.min
+}
+
+// Max returns the maximum number that has been added to the sketch, or 0 if no
+// numbers have been added.
+func (s *Sketch[T]) Max() T {
+	return s.max
+}
+
+// Quantile returns an approximation of the q-th quantile of the numbers that
+// have been added to the sketch, where q is clamped to [0,1]. The result is
+// always one of the numbers that was added, or 0 if no numbers have been
+// added.
+func (s *Sketch[T]) Quantile(q float64) T {
+	switch {
+	case s.count == 0:
+		return 0
+	case !(q > 0):
+		return s.min
+	case q >= 1:
+		return s.max
+	}
+
+	var (
+		items  = s.weighted()
+		target = int(math.Ceil(q * float64(s.count)))
+		rank   int
+	)
+
+	for _, item := range items {
+		rank += item.weight
+		if rank >= target {
+			return item.value
+		}
+	}
+
+	return s.max
+}
+
+// CDF returns an approximation of the fraction of numbers added to the sketch
+// that are less than or equal to x, or NaN if no numbers have been added.
+func (s *Sketch[T]) CDF(x T) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+
+	var rank int
+	for h, level := range s.levels {
+		for _, value := range level {
+			if value <= x {
+				rank += 1 << h
+			}
+		}
+	}
+
+	return float64(rank) / float64(s.count)
+}
+
+// MarshalBinary encodes the sketch into a compact binary form. Values are
+// encoded using the width of T, so smaller types produce smaller encodings.
+func (s *Sketch[T]) MarshalBinary() ([]byte, error) {
+	k := s.k
+	if k <= 0 {
+		k = DefaultSketchSize
+	}
+
+	width := BitSize[T]() / 8
+
+	b := make([]byte, 0, 2+8*(3+len(s.levels))+width*(2+s.size))
+	b = append(b, sketchVersion, sketchKind[T]())
+	b = appendUint64(b, uint64(k))
+	b = appendUint64(b, uint64(s.count))
+	b = appendSketchValue(b, s.min)
+	b = appendSketchValue(b, s.max)
+	b = appendUint64(b, uint64(len(s.levels)))
+
+	for _, level := range s.levels {
+		b = appendUint64(b, uint64(len(level)))
+		for _, value := range level {
+			b = appendSketchValue(b, value)
+		}
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary decodes a sketch that was encoded with MarshalBinary,
+// replacing the contents of s other than its source of randomness.
+// ErrInvalidSketch is returned if data is not a valid encoding of a Sketch of
+// the same type.
+func (s *Sketch[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != sketchVersion || data[1] != sketchKind[T]() {
+		return ErrInvalidSketch
+	}
+
+	var (
+		d       = sketchDecoder{data: data[2:]}
+		k       = d.next()
+		count   = d.next()
+		lo      = decodeSketchValue[T](&d)
+		hi      = decodeSketchValue[T](&d)
+		nlevels = d.next()
+	)
+
+	if d.err || k == 0 || k > math.MaxInt32 || nlevels > 64 {
+		return ErrInvalidSketch
+	}
+
+	decoded := Sketch[T]{
+		src:   s.src,
+		k:     int(k),
+		count: int(count),
+		min:   lo,
+		max:   hi,
+	}
+
+	decoded.decodeLevels(&d, nlevels)
+	if d.err || len(d.data) != 0 {
+		return ErrInvalidSketch
+	}
+
+	*s = decoded
+
+	return nil
+}
+
+// decodeLevels decodes n levels from d into s. If the levels are truncated or
+// their total weight does not match the count of s, d.err is set.
+func (s *Sketch[T]) decodeLevels(d *sketchDecoder, n uint64) {
+	var (
+		width  = uint64(BitSize[T]() / 8)
+		weight uint64
+	)
+
+	for h := uint64(0); h < n && !d.err; h++ {
+		size := d.next()
+		if size > uint64(len(d.data))/width {
+			d.err = true
+			break
+		}
+
+		level := make([]T, size)
+		for i := range level {
+			level[i] = decodeSketchValue[T](d)
+		}
+
+		s.levels = append(s.levels, level)
+		s.size += len(level)
+		weight += size << h
+	}
+
+	if weight != uint64(s.count) {
+		d.err = true
+	}
+
+	if len(s.levels) == 0 {
+		s.grow()
+	} else {
+		s.updateMaxSize()
+	}
+}
+
+type sketchItem[T Numeric] struct {
+	value  T
+	weight int
+}
+
+// weighted returns all retained values, sorted, along with their weights.
+func (s *Sketch[T]) weighted() []sketchItem[T] {
+	items := make([]sketchItem[T], 0, s.size)
+	for h, level := range s.levels {
+		for _, value := range level {
+			items = append(items, sketchItem[T]{
+				value:  value,
+				weight: 1 << h,
+			})
+		}
+	}
+
+	slices.SortFunc(items, func(a sketchItem[T], b sketchItem[T]) bool {
+		return a.value < b.value
+	})
+
+	return items
+}
+
+func (s *Sketch[T]) init(k int) {
+	if k <= 0 {
+		k = DefaultSketchSize
+	}
+
+	*s = Sketch[T]{src: s.src, k: k}
+	s.grow()
+}
+
+func (s *Sketch[T]) grow() {
+	s.levels = append(s.levels, nil)
+	s.updateMaxSize()
+}
+
+func (s *Sketch[T]) updateMaxSize() {
+	s.maxSize = 0
+	for h := range s.levels {
+		s.maxSize += s.capacity(h)
+	}
+}
+
+// capacity returns the number of values that level h may hold before being
+// compacted. Capacities decay geometrically from the top level down, so that
+// lower levels (whose values have less weight) hold fewer values.
+func (s *Sketch[T]) capacity(h int) int {
+	depth := len(s.levels) - h - 1
+	return Max(int(math.Ceil(float64(s.k)*math.Pow(sketchDecay, float64(depth)))), 2)
+}
+
+// compress compacts the lowest level that is at or above its capacity.
+func (s *Sketch[T]) compress() {
+	for h := range s.levels {
+		if len(s.levels[h]) < s.capacity(h) {
+			continue
+		}
+
+		if h+1 == len(s.levels) {
+			s.grow()
+		}
+
+		s.compact(h)
+		return
+	}
+}
+
+// compact sorts level h and promotes every other value into level h+1 (with
+// twice the weight), starting with a random value from the first pair. If the
+// level has an odd number of values, the smallest value is left behind.
+func (s *Sketch[T]) compact(h int) {
+	var (
+		values = s.levels[h]
+		start  = len(values) % 2
+	)
+
+	slices.Sort(values)
+
+	for i := start + int(sourceOrFast(s.src).Uint64()>>63); i < len(values); i += 2 {
+		s.levels[h+1] = append(s.levels[h+1], values[i])
+	}
+
+	s.levels[h] = values[:start]
+	s.size -= (len(values) - start) / 2
+}
+
+// sketchKind returns a byte that identifies T in encoded sketches.
+func sketchKind[T Numeric]() byte {
+	kind := byte(BitSize[T]() / 8)
+	if IsSigned[T]() {
+		kind |= 0x80
+	}
+	if IsFloat[T]() {
+		kind |= 0x40
+	}
+	return kind
+}
+
+// appendSketchValue appends the little-endian encoding of x, using the width
+// of T, to b.
+func appendSketchValue[T Numeric](b []byte, x T) []byte {
+	var u uint64
+
+	switch {
+	case IsFloat[T]() && BitSize[T]() == 32:
+		u = uint64(math.Float32bits(float32(x)))
+	case IsFloat[T]():
+		u = math.Float64bits(float64(x))
+	default:
+		u = uint64(x)
+	}
+
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], u)
+	return append(b, buf[:BitSize[T]()/8]...)
+}
+
+// decodeSketchValue decodes a value that was encoded with appendSketchValue.
+func decodeSketchValue[T Numeric](d *sketchDecoder) T {
+	u := d.read(BitSize[T]() / 8)
+
+	switch {
+	case IsFloat[T]() && BitSize[T]() == 32:
+		return T(math.Float32frombits(uint32(u)))
+	case IsFloat[T]():
+		return T(math.Float64frombits(u))
+	default:
+		// Truncating u restores the sign of signed values.
+		return T(u)
+	}
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+type sketchDecoder struct {
+	data []byte
+	err  bool
+}
+
+func (d *sketchDecoder) next() uint64 {
+	return d.read(8)
+}
+
+// read decodes a little-endian unsigned integer of n bytes, where n <= 8.
+func (d *sketchDecoder) read(n int) uint64 {
+	if len(d.data) < n {
+		d.err = true
+		return 0
+	}
+
+	var buf [8]byte
+	copy(buf[:], d.data[:n])
+	d.data = d.data[n:]
+	return binary.LittleEndian.Uint64(buf[:])
+}
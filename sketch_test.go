@@ -0,0 +1,316 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"fmt"
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+	"golang.org/x/exp/slices"
+)
+
+const maxSketchRankError = 0.02
+
+func TestSketchEmpty(t *testing.T) {
+	var sketch math.Sketch[int]
+
+	require.Equal(t, 0, sketch.Count())
+	require.Equal(t, 0, sketch.Min())
+	require.Equal(t, 0, sketch.Max())
+	require.Equal(t, 0, sketch.Quantile(0.5))
+	require.True(t, stdmath.IsNaN(sketch.CDF(0)))
+
+	sketch.Add(10)
+	require.Equal(t, 1, sketch.Count())
+	require.Equal(t, 10, sketch.Quantile(0))
+	require.Equal(t, 10, sketch.Quantile(0.5))
+	require.Equal(t, 10, sketch.Quantile(1))
+	require.Equal(t, 0.0, sketch.CDF(9))
+	require.Equal(t, 1.0, sketch.CDF(10))
+
+	sketch.Reset()
+	require.Equal(t, 0, sketch.Count())
+	require.Equal(t, 0, sketch.Quantile(0.5))
+}
+
+func TestSketchExact(t *testing.T) {
+	// Sketches that have not yet compacted retain every value, and so should
+	// agree exactly with the nearest-rank method.
+	var (
+		sketch = math.NewSketch[int](100, nil)
+		values = make([]int, 50)
+	)
+
+	for i := range values {
+		values[i] = (i * 7919) % 101
+		sketch.Add(values[i])
+	}
+
+	for q := 0.0; q <= 1; q += 0.05 {
+		want := math.QuantileWith(values, q, math.QuantileNearestRank)
+		require.Equal(t, int(want), sketch.Quantile(q), "q=%v", q)
+	}
+
+	require.Equal(t, math.MinN(values...), sketch.Min())
+	require.Equal(t, math.MaxN(values...), sketch.Max())
+}
+
+func TestSketchRankError(t *testing.T) {
+	const size = 100000
+
+	t.Run("uniform int64", func(t *testing.T) {
+		var (
+			sketch math.Sketch[int64]
+			values = make([]int64, size)
+		)
+
+		for i := range values {
+			values[i] = math.Fastrandn[int64](1 << 30)
+			sketch.Add(values[i])
+		}
+
+		requireSketchRankError(t, &sketch, values)
+	})
+
+	t.Run("exponential float64", func(t *testing.T) {
+		var (
+			sketch math.Sketch[float64]
+			values = make([]float64, size)
+		)
+
+		for i := range values {
+			u := (float64(math.Fastrand[uint32]()) + 1) / (1 << 32)
+			values[i] = -stdmath.Log(u)
+			sketch.Add(values[i])
+		}
+
+		requireSketchRankError(t, &sketch, values)
+	})
+
+	t.Run("latency durations", func(t *testing.T) {
+		var (
+			sketch = math.NewSketch[time.Duration](200, nil)
+			values = make([]time.Duration, size)
+		)
+
+		for i := range values {
+			values[i] = time.Millisecond +
+				math.Fastrandn[time.Duration](10)*math.Fastrandn[time.Duration](time.Millisecond)
+			sketch.Add(values[i])
+		}
+
+		requireSketchRankError(t, sketch, values)
+	})
+}
+
+func TestSketchMerge(t *testing.T) {
+	var (
+		merged math.Sketch[uint32]
+		parts  [4]math.Sketch[uint32]
+		values = make([]uint32, 100000)
+	)
+
+	for i := range values {
+		values[i] = math.Fastrand[uint32]()
+		parts[i%len(parts)].Add(values[i])
+	}
+
+	merged.Merge(&math.Sketch[uint32]{})
+	for i := range parts {
+		merged.Merge(&parts[i])
+	}
+
+	require.Equal(t, len(values), merged.Count())
+	requireSketchRankError(t, &merged, values)
+
+	merged.Merge(&merged)
+	require.Equal(t, 2*len(values), merged.Count())
+	requireSketchRankError(t, &merged, append(values, values...))
+}
+
+func TestSketchSource(t *testing.T) {
+	var (
+		a = math.NewSketch[int](20, math.NewRNG(1))
+		b = math.NewSketch[int](20, math.NewRNG(1))
+	)
+
+	// Sketches using equally seeded sources should compact identically, even
+	// after being reset.
+	for round := 0; round < 2; round++ {
+		a.Reset()
+		b.Reset()
+
+		for i := 0; i < 10000; i++ {
+			a.Add(i)
+			b.Add(i)
+		}
+
+		adata, err := a.MarshalBinary()
+		require.NoError(t, err)
+		bdata, err := b.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, adata, bdata)
+	}
+}
+
+func TestSketchMarshal(t *testing.T) {
+	var sketch math.Sketch[int16]
+	for i := 0; i < 10000; i++ {
+		sketch.Add(int16(math.Fastrandn(1000) - 500))
+	}
+
+	data, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded math.Sketch[int16]
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, sketch.Count(), decoded.Count())
+	require.Equal(t, sketch.Min(), decoded.Min())
+	require.Equal(t, sketch.Max(), decoded.Max())
+
+	for q := 0.0; q <= 1; q += 0.01 {
+		require.Equal(t, sketch.Quantile(q), decoded.Quantile(q))
+	}
+
+	redata, err := decoded.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, redata)
+
+	// The decoded sketch should remain usable.
+	decoded.Add(1000)
+	require.Equal(t, int16(1000), decoded.Max())
+
+	var empty math.Sketch[int16]
+	data, err = empty.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, 0, decoded.Count())
+	decoded.Add(1)
+	require.Equal(t, int16(1), decoded.Quantile(0.5))
+}
+
+func TestSketchMarshalWidth(t *testing.T) {
+	testSketchMarshalWidth[uint8](t)
+	testSketchMarshalWidth[int8](t)
+	testSketchMarshalWidth[int32](t)
+	testSketchMarshalWidth[float32](t)
+	testSketchMarshalWidth[float64](t)
+}
+
+func testSketchMarshalWidth[T math.Numeric](t *testing.T) {
+	t.Run(fmt.Sprintf("%T", T(0)), func(t *testing.T) {
+		var (
+			sketch math.Sketch[T]
+			zero   T
+		)
+
+		for i := 0; i < 300; i++ {
+			sketch.Add(zero + T(i%100) - 50)
+		}
+
+		data, err := sketch.MarshalBinary()
+		require.NoError(t, err)
+
+		// Values, including the min and max, are encoded using the width of
+		// T, and the sketch retains at most every value that was added.
+		width := math.BitSize[T]() / 8
+		require.LessOrEqual(t, len(data), 64+width*(2+300))
+
+		var decoded math.Sketch[T]
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.Equal(t, sketch.Min(), decoded.Min())
+		require.Equal(t, sketch.Max(), decoded.Max())
+
+		for q := 0.0; q <= 1; q += 0.05 {
+			require.Equal(t, sketch.Quantile(q), decoded.Quantile(q))
+		}
+	})
+}
+
+func TestSketchUnmarshalErrors(t *testing.T) {
+	var sketch math.Sketch[float64]
+	for i := 0; i < 1000; i++ {
+		sketch.Add(float64(i))
+	}
+
+	data, err := sketch.MarshalBinary()
+	require.NoError(t, err)
+
+	var (
+		wrongType math.Sketch[int64]
+		decoded   math.Sketch[float64]
+	)
+
+	require.ErrorIs(t, wrongType.UnmarshalBinary(data), math.ErrInvalidSketch)
+	require.ErrorIs(t, decoded.UnmarshalBinary(nil), math.ErrInvalidSketch)
+	require.ErrorIs(t, decoded.UnmarshalBinary(data[:len(data)-1]), math.ErrInvalidSketch)
+	require.ErrorIs(t, decoded.UnmarshalBinary(append(data, 0)), math.ErrInvalidSketch)
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[0]++
+	require.ErrorIs(t, decoded.UnmarshalBinary(corrupt), math.ErrInvalidSketch)
+
+	// Change the count so that it no longer agrees with the levels.
+	corrupt = append([]byte(nil), data...)
+	corrupt[10]++
+	require.ErrorIs(t, decoded.UnmarshalBinary(corrupt), math.ErrInvalidSketch)
+
+	require.Equal(t, 0, decoded.Count())
+}
+
+func requireSketchRankError[T math.Numeric](t *testing.T, sketch *math.Sketch[T], values []T) {
+	t.Helper()
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	size := float64(len(sorted))
+	require.Equal(t, sorted[0], sketch.Min())
+	require.Equal(t, sorted[len(sorted)-1], sketch.Max())
+
+	for q := 0.01; q < 1; q += 0.01 {
+		var (
+			x      = sketch.Quantile(q)
+			lo, _  = slices.BinarySearch(sorted, x)
+			hi     = lo
+			target = q * size
+		)
+
+		for hi < len(sorted) && sorted[hi] == x {
+			hi++
+		}
+
+		var rankErr float64
+		switch {
+		case target < float64(lo):
+			rankErr = (float64(lo) - target) / size
+		case target > float64(hi):
+			rankErr = (target - float64(hi)) / size
+		}
+
+		require.LessOrEqual(t, rankErr, maxSketchRankError, "q=%v", q)
+		require.InDelta(t, float64(hi)/size, sketch.CDF(x), maxSketchRankError, "q=%v", q)
+	}
+}
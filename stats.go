@@ -0,0 +1,142 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import "math"
+
+// Stats is a streaming statistics accumulator that tracks the count, sum,
+// minimum, maximum, mean, and variance of the numbers added to it without
+// retaining them. The mean and variance are updated using Welford's algorithm,
+// which is numerically stable for long streams of large values.
+//
+// The zero value is ready to use. Stats is not safe for concurrent use; each
+// goroutine should use its own Stats, combining them with Merge.
+type Stats[T Numeric] struct {
+	count int
+	sum   T
+	min   T
+	max   T
+	mean  float64
+	m2    float64
+}
+
+// Add adds x to the accumulator.
+func (s *Stats[T]) Add(x T) {
+	if s.count == 0 {
+		s.min, s.max = x, x
+	} else {
+		s.min = Min(s.min, x)
+		s.max = Max(s.max, x)
+	}
+
+	s.count++
+	s.sum += x
+
+	delta := float64(x) - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (float64(x) - s.mean)
+}
+
+// Merge adds all numbers accumulated by other to s, as if they had been added
+// to s directly.
+func (s *Stats[T]) Merge(other *Stats[T]) {
+	switch {
+	case other.count == 0:
+		return
+	case s.count == 0:
+		*s = *other
+		return
+	}
+
+	var (
+		na    = float64(s.count)
+		nb    = float64(other.count)
+		n     = na + nb
+		delta = other.mean - s.mean
+	)
+
+	s.count += other.count
+	s.sum += other.sum
+	s.min = Min(s.min, other.min)
+	s.max = Max(s.max, other.max)
+	s.mean += delta * nb / n
+	s.m2 += other.m2 + delta*delta*na*nb/n
+}
+
+// Reset clears all accumulated state.
+func (s *Stats[T]) Reset() {
+	*s = Stats[T]{}
+}
+
+// Count returns the number of numbers that have been added.
+func (s *Stats[T]) Count() int {
+	return s.count
+}
+
+// Sum returns the sum of all numbers that have been added. Like Mean, the sum
+// is accumulated in T and may wrap for integer types.
+func (s *Stats[T]) Sum() T {
+	return s.sum
+}
+
+// Min returns the minimum number that has been added, or 0 if no numbers have
+// been added.
+func (s *Stats[T]) Min() T {
+	return s.min
+}
+
+// Max returns the maximum number that has been added, or 0 if no numbers have
+// been added.
+func (s *Stats[T]) Max() T {
+	return s.max
+}
+
+// Mean returns the average of all numbers that have been added, or 0 if no
+// numbers have been added.
+func (s *Stats[T]) Mean() float64 {
+	return s.mean
+}
+
+// Variance returns the population variance of all numbers that have been
+// added, or 0 if no numbers have been added.
+func (s *Stats[T]) Variance() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	return s.m2 / float64(s.count)
+}
+
+// SampleVariance returns the sample (Bessel-corrected) variance of all numbers
+// that have been added, or 0 if fewer than two numbers have been added.
+func (s *Stats[T]) SampleVariance() float64 {
+	if s.count < 2 {
+		return 0
+	}
+
+	return s.m2 / float64(s.count-1)
+}
+
+// StdDev returns the population standard deviation of all numbers that have
+// been added, or 0 if no numbers have been added.
+func (s *Stats[T]) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestStats(t *testing.T) {
+	testStats[int](t)
+	testStats[int8](t)
+	testStats[int16](t)
+	testStats[int32](t)
+	testStats[int64](t)
+	testStats[uint](t)
+	testStats[uint8](t)
+	testStats[uint16](t)
+	testStats[uint32](t)
+	testStats[uint64](t)
+	testStats[time.Duration](t)
+	testStats[float32](t)
+	testStats[float64](t)
+}
+
+func TestStatsEmpty(t *testing.T) {
+	var stats math.Stats[int]
+
+	require.Equal(t, 0, stats.Count())
+	require.Equal(t, 0, stats.Sum())
+	require.Equal(t, math.MinN[int](), stats.Min())
+	require.Equal(t, math.MaxN[int](), stats.Max())
+	require.Equal(t, 0.0, stats.Mean())
+	require.Equal(t, 0.0, stats.Variance())
+	require.Equal(t, 0.0, stats.SampleVariance())
+	require.Equal(t, 0.0, stats.StdDev())
+
+	stats.Add(10)
+	require.Equal(t, 10.0, stats.Mean())
+	require.Equal(t, 0.0, stats.Variance())
+	require.Equal(t, 0.0, stats.SampleVariance())
+}
+
+func TestStatsMerge(t *testing.T) {
+	var (
+		all   math.Stats[float64]
+		parts [3]math.Stats[float64]
+	)
+
+	for i := 0; i < 1000; i++ {
+		x := float64(i*i%97) - 48.5
+		all.Add(x)
+		parts[i%len(parts)].Add(x)
+	}
+
+	var merged math.Stats[float64]
+	merged.Merge(&math.Stats[float64]{})
+	for i := range parts {
+		merged.Merge(&parts[i])
+	}
+	merged.Merge(&math.Stats[float64]{})
+
+	require.Equal(t, all.Count(), merged.Count())
+	require.InDelta(t, all.Sum(), merged.Sum(), 1e-9)
+	require.Equal(t, all.Min(), merged.Min())
+	require.Equal(t, all.Max(), merged.Max())
+	require.InDelta(t, all.Mean(), merged.Mean(), 1e-9)
+	require.InDelta(t, all.Variance(), merged.Variance(), 1e-9)
+	require.InDelta(t, all.SampleVariance(), merged.SampleVariance(), 1e-9)
+
+	merged.Reset()
+	require.Equal(t, math.Stats[float64]{}, merged)
+}
+
+func TestStatsStability(t *testing.T) {
+	var stats math.Stats[float64]
+
+	for _, x := range []float64{4, 7, 13, 16} {
+		stats.Add(1e9 + x)
+	}
+
+	require.InDelta(t, 1e9+10, stats.Mean(), 1e-6)
+	require.InDelta(t, 22.5, stats.Variance(), 1e-6)
+	require.InDelta(t, 30.0, stats.SampleVariance(), 1e-6)
+}
+
+func testStats[T math.Numeric](t *testing.T) {
+	var stats math.Stats[T]
+
+	for _, x := range []T{2, 4, 4, 4, 5, 5, 7, 9} {
+		stats.Add(x)
+	}
+
+	require.Equal(t, 8, stats.Count())
+	require.Equal(t, T(40), stats.Sum())
+	require.Equal(t, T(2), stats.Min())
+	require.Equal(t, T(9), stats.Max())
+	require.Equal(t, 5.0, stats.Mean())
+	require.Equal(t, 4.0, stats.Variance())
+	require.InDelta(t, 32.0/7.0, stats.SampleVariance(), 1e-12)
+	require.Equal(t, 2.0, stats.StdDev())
+}
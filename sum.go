@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// pairwiseBlockSize is the length below which PairwiseSum sums naively, which
+// keeps recursion overhead low without affecting the error bound much.
+const pairwiseBlockSize = 128
+
+// Sum returns the sum of all given numbers, accumulated in T. For integer
+// types the sum may wrap, and for floating point types rounding error grows
+// with the length of x; see KahanSum and PairwiseSum for more accurate
+// alternatives.
+func Sum[T Numeric](x ...T) T {
+	var total T
+
+	for len(x) >= 8 {
+		total += x[0] + x[1] + x[2] + x[3] + x[4] + x[5] + x[6] + x[7]
+		x = x[8:]
+	}
+
+	for _, n := range x {
+		total += n
+	}
+
+	return total
+}
+
+// KahanSum returns the sum of all given numbers using Neumaier's variant of
+// Kahan compensated summation, accumulated in float64. The error of the result
+// does not grow with the length of x, and cancellation is handled correctly;
+// e.g. KahanSum(1e16, 1, -1e16) is 1 where Sum returns 0. If the sum is
+// infinite or NaN, the uncompensated sum is returned.
+func KahanSum[T constraints.Float](x ...T) T {
+	return T(kahanSum(x))
+}
+
+// kahanSum returns the compensated sum of x as a float64.
+func kahanSum[T constraints.Float](x []T) float64 {
+	var sum, c float64
+
+	for _, n := range x {
+		v := float64(n)
+		t := sum + v
+
+		// Recover the low-order bits lost by the addition from whichever
+		// operand was smaller in magnitude.
+		if math.Abs(sum) >= math.Abs(v) {
+			c += (sum - t) + v
+		} else {
+			c += (v - t) + sum
+		}
+
+		sum = t
+	}
+
+	if math.IsInf(sum, 0) || math.IsNaN(sum) {
+		return sum
+	}
+
+	return sum + c
+}
+
+// PairwiseSum returns the sum of all given numbers by recursively summing each
+// half of x, which bounds rounding error by O(log n) rather than the O(n) of
+// Sum at a similar cost. Unlike KahanSum, it does not correct for
+// cancellation; e.g. PairwiseSum(1e16, 1, -1e16) is 0.
+func PairwiseSum[T constraints.Float](x ...T) T {
+	if len(x) <= pairwiseBlockSize {
+		return Sum(x...)
+	}
+
+	half := len(x) / 2
+	return PairwiseSum(x[:half]...) + PairwiseSum(x[half:]...)
+}
+
+// MeanPrecise returns the average value of all given numbers, using the same
+// compensated summation as KahanSum. The sum is not rounded to T before
+// dividing, so the mean of float32 values is accurate even if their sum
+// overflows float32. NaN is returned if no numbers are given.
+func MeanPrecise[T constraints.Float](x ...T) T {
+	if len(x) == 0 {
+		return T(math.NaN())
+	}
+
+	return T(kahanSum(x) / float64(len(x)))
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestSum(t *testing.T) {
+	require.Equal(t, 0, math.Sum[int]())
+	require.Equal(t, 55, math.Sum(1, 2, 3, 4, 5, 6, 7, 8, 9, 10))
+	require.Equal(t, uint8(4), math.Sum[uint8](255, 5))
+	require.Equal(t, time.Minute, math.Sum(30*time.Second, 20*time.Second, 10*time.Second))
+	require.Equal(t, 0.0, math.Sum(1e16, 1, -1e16))
+	require.Equal(t, 6.5, math.Sum(1.5, 2, 3))
+}
+
+func TestKahanSum(t *testing.T) {
+	require.Equal(t, 0.0, math.KahanSum[float64]())
+	require.Equal(t, 1.0, math.KahanSum(1e16, 1, -1e16))
+	require.Equal(t, 2.0, math.KahanSum(1, 1e100, 1, -1e100))
+	require.Equal(t, 1.0, math.KahanSum(1e16, 1, -1e16, 1e-16, -1e-16))
+	require.Equal(t, float32(1), math.KahanSum[float32](1e8, 1, -1e8))
+	require.Equal(t, 6.5, math.KahanSum(1.5, 2, 3))
+
+	require.True(t, stdmath.IsInf(math.KahanSum(1, stdmath.Inf(1), -1e16), 1))
+	require.True(t, stdmath.IsInf(math.KahanSum(stdmath.MaxFloat64, stdmath.MaxFloat64), 1))
+	require.True(t, stdmath.IsNaN(math.KahanSum(1, stdmath.NaN())))
+	require.True(t, stdmath.IsNaN(math.KahanSum(stdmath.Inf(1), stdmath.Inf(-1))))
+}
+
+func TestPairwiseSum(t *testing.T) {
+	require.Equal(t, 0.0, math.PairwiseSum[float64]())
+	require.Equal(t, 6.5, math.PairwiseSum(1.5, 2, 3))
+
+	// Pairwise summation does not correct for cancellation.
+	require.Equal(t, 0.0, math.PairwiseSum(1e16, 1, -1e16))
+
+	x := make([]float64, 1000)
+	for i := range x {
+		x[i] = float64(i)
+	}
+	require.Equal(t, 499500.0, math.PairwiseSum(x...))
+}
+
+func TestSumAccuracy(t *testing.T) {
+	// Many small values that are inexact in binary.
+	tenths := make([]float64, 1e6)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+
+	// Values of wildly mixed magnitude and sign.
+	var (
+		rng   = math.NewRNG(1)
+		mixed = make([]float64, 1e5)
+	)
+	for i := range mixed {
+		mixed[i] = (math.Float[float64](rng) - 0.5) * stdmath.Pow(10, float64(math.Intn(rng, 30)))
+	}
+
+	for _, x := range [][]float64{tenths, mixed} {
+		var (
+			want     = exactSum(x)
+			naive    = relativeError(want, math.Sum(x...))
+			pairwise = relativeError(want, math.PairwiseSum(x...))
+			kahan    = relativeError(want, math.KahanSum(x...))
+		)
+
+		require.Less(t, pairwise, naive)
+		require.LessOrEqual(t, kahan, pairwise)
+		require.Less(t, kahan, 1e-15)
+	}
+
+	// float32 values are accumulated in float64.
+	tenths32 := make([]float32, 1e6)
+	for i := range tenths32 {
+		tenths32[i] = 0.1
+	}
+	require.InEpsilon(t, 1e5, math.KahanSum(tenths32...), 1e-6)
+	require.InEpsilon(t, 1e5, math.PairwiseSum(tenths32...), 1e-5)
+	require.NotEqual(t, float32(1e5), math.Sum(tenths32...))
+}
+
+func TestMeanPrecise(t *testing.T) {
+	require.Equal(t, 1.0/3, math.MeanPrecise(1e16, 1, -1e16))
+	require.Equal(t, 2.0, math.MeanPrecise(1.0, 2, 3))
+	require.True(t, stdmath.IsNaN(math.MeanPrecise[float64]()))
+
+	// The float32 sum overflows, but the mean does not.
+	max32 := math.MaxValue[float32]()
+	require.Equal(t, max32, math.MeanPrecise(max32, max32, max32))
+	require.True(t, stdmath.IsInf(float64(math.Mean(max32, max32, max32)), 1))
+}
+
+// exactSum returns the sum of x rounded from an exact computation.
+func exactSum(x []float64) float64 {
+	sum := new(big.Float).SetPrec(2048)
+	for _, v := range x {
+		sum.Add(sum, new(big.Float).SetFloat64(v))
+	}
+
+	f, _ := sum.Float64()
+	return f
+}
+
+func relativeError(want float64, got float64) float64 {
+	return stdmath.Abs(got-want) / stdmath.Abs(want)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// ErrInvalidWeights indicates that a set of weights contained a negative or
+// non-finite weight, or did not contain any positive weight.
+var ErrInvalidWeights = errors.New("math: weights must be finite, non-negative, and not all zero")
+
+// WeightedIndex returns a random index of weights, chosen with probability
+// proportional to its weight, using FastSource. It takes O(n) time; use a
+// WeightedChooser to make many choices from the same weights. If weights is
+// invalid (see ErrInvalidWeights), -1 is returned.
+func WeightedIndex[T Numeric](weights []T) int {
+	total, ok := weightsTotal(weights)
+	if !ok {
+		return -1
+	}
+
+	var (
+		target = Float[float64](FastSource) * total
+		last   int
+		sum    float64
+	)
+
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+
+		sum += float64(w)
+		if target < sum {
+			return i
+		}
+		last = i
+	}
+
+	// Rounding can leave target at or just above the sum; fall back to the
+	// last index that can be chosen.
+	return last
+}
+
+// WeightedChooser makes random weighted choices in O(1) time using the alias
+// method of Vose (1991). Choose and ChooseFrom are safe for concurrent use,
+// provided that the weights are not updated concurrently. A zero-value
+// WeightedChooser has no weights, and chooses -1 until SetWeights is called.
+type WeightedChooser[T Numeric] struct {
+	weights []T
+	prob    []float64
+	alias   []int
+}
+
+// NewWeightedChooser returns a new WeightedChooser that chooses indices of
+// weights with probability proportional to their weights. The weights are
+// copied. ErrInvalidWeights is returned if the weights are invalid.
+func NewWeightedChooser[T Numeric](weights []T) (*WeightedChooser[T], error) {
+	c := &WeightedChooser[T]{}
+	if err := c.SetWeights(weights); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Choose returns a random index using FastSource, or -1 if c has no weights.
+func (c *WeightedChooser[T]) Choose() int {
+	return c.ChooseFrom(FastSource)
+}
+
+// ChooseFrom returns a random index using src, or -1 if c has no weights.
+func (c *WeightedChooser[T]) ChooseFrom(src Source) int {
+	if len(c.prob) == 0 {
+		return -1
+	}
+
+	i := Intn(src, len(c.prob))
+	if Float[float64](src) < c.prob[i] {
+		return i
+	}
+	return c.alias[i]
+}
+
+// Len returns the number of weights.
+func (c *WeightedChooser[T]) Len() int {
+	return len(c.weights)
+}
+
+// Weight returns the weight of index i.
+func (c *WeightedChooser[T]) Weight(i int) T {
+	return c.weights[i]
+}
+
+// Update sets the weight of index i to w and rebuilds the chooser in O(n)
+// time. If the resulting weights are invalid, ErrInvalidWeights is returned
+// and the chooser is unchanged.
+func (c *WeightedChooser[T]) Update(i int, w T) error {
+	prev := c.weights[i]
+	c.weights[i] = w
+
+	if err := c.build(); err != nil {
+		c.weights[i] = prev
+		return err
+	}
+
+	return nil
+}
+
+// SetWeights replaces all weights, which are copied, and rebuilds the chooser
+// in O(n) time. If the weights are invalid, ErrInvalidWeights is returned and
+// the chooser is unchanged.
+func (c *WeightedChooser[T]) SetWeights(weights []T) error {
+	prev := c.weights
+	c.weights = slices.Clone(weights)
+
+	if err := c.build(); err != nil {
+		c.weights = prev
+		return err
+	}
+
+	return nil
+}
+
+func (c *WeightedChooser[T]) build() error {
+	total, ok := weightsTotal(c.weights)
+	if !ok {
+		return ErrInvalidWeights
+	}
+
+	var (
+		n      = len(c.weights)
+		prob   = make([]float64, n)
+		alias  = make([]int, n)
+		scaled = make([]float64, n)
+		small  = make([]int, 0, n)
+		large  = make([]int, 0, n)
+	)
+
+	// Scale the weights so that their mean is 1, then pair each index with
+	// less than its share with an index that has more than its share.
+	for i, w := range c.weights {
+		scaled[i] = float64(w) / total * float64(n)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		var (
+			l = small[len(small)-1]
+			g = large[len(large)-1]
+		)
+
+		small = small[:len(small)-1]
+		prob[l] = scaled[l]
+		alias[l] = g
+
+		scaled[g] = (scaled[g] + scaled[l]) - 1
+		if scaled[g] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, g)
+		}
+	}
+
+	// Anything left over has a share of 1, give or take rounding error.
+	for _, i := range large {
+		prob[i] = 1
+	}
+	for _, i := range small {
+		prob[i] = 1
+	}
+
+	c.prob, c.alias = prob, alias
+	return nil
+}
+
+// weightsTotal returns the sum of weights as a float64, reporting false if
+// the weights are invalid.
+func weightsTotal[T Numeric](weights []T) (float64, bool) {
+	var total float64
+	for _, w := range weights {
+		f := float64(w)
+		if f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		total += f
+	}
+
+	return total, total > 0 && !math.IsInf(total, 0)
+}
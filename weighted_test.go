@@ -0,0 +1,163 @@
+// Copyright (c) 2022 Matt Way
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE THE SOFTWARE.
+
+package math_test
+
+import (
+	stdmath "math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mway.dev/math"
+)
+
+func TestWeightedIndex(t *testing.T) {
+	weights := []int{5, 0, 1, 10, 0, 4}
+	testWeighted(t, weights, func() int {
+		i := math.WeightedIndex(weights)
+		require.NotZero(t, weights[i], i)
+		return i
+	})
+
+	fweights := []float64{0.25, 1e-3, 0, 0.5, 0.249}
+	testWeighted(t, fweights, func() int { return math.WeightedIndex(fweights) })
+
+	require.Equal(t, 0, math.WeightedIndex([]uint8{1}))
+	require.Equal(t, 2, math.WeightedIndex([]uint8{0, 0, 1}))
+	require.Equal(t, -1, math.WeightedIndex([]int(nil)))
+	require.Equal(t, -1, math.WeightedIndex([]int{0, 0}))
+	require.Equal(t, -1, math.WeightedIndex([]int{1, -1, 2}))
+	require.Equal(t, -1, math.WeightedIndex([]float64{1, stdmath.NaN()}))
+	require.Equal(t, -1, math.WeightedIndex([]float64{1, stdmath.Inf(1)}))
+	require.Equal(t, -1, math.WeightedIndex([]float64{stdmath.MaxFloat64, stdmath.MaxFloat64}))
+}
+
+func TestWeightedChooser(t *testing.T) {
+	var (
+		weights = []time.Duration{5, 0, 1, 10, 0, 4, 7, 3}
+		rng     = math.NewRNG(1)
+	)
+
+	chooser, err := math.NewWeightedChooser(weights)
+	require.NoError(t, err)
+	require.Equal(t, len(weights), chooser.Len())
+
+	testWeighted(t, weights, func() int {
+		i := chooser.ChooseFrom(rng)
+		require.NotZero(t, weights[i], i)
+		return i
+	})
+	testWeighted(t, weights, chooser.Choose)
+
+	// The chooser owns a copy of the weights.
+	weights[0] = 100
+	require.Equal(t, time.Duration(5), chooser.Weight(0))
+
+	// A single weight is always chosen.
+	single, err := math.NewWeightedChooser([]float32{0.5})
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		require.Equal(t, 0, single.Choose())
+	}
+
+	// Extreme skew.
+	skewed := []uint64{1, 1 << 40, 1}
+	chooser2, err := math.NewWeightedChooser(skewed)
+	require.NoError(t, err)
+	testWeighted(t, skewed, func() int { return chooser2.ChooseFrom(rng) })
+}
+
+func TestWeightedChooserUpdate(t *testing.T) {
+	var (
+		weights = []float64{1, 2, 3, 4}
+		rng     = math.NewRNG(2)
+	)
+
+	chooser, err := math.NewWeightedChooser(weights)
+	require.NoError(t, err)
+
+	require.NoError(t, chooser.Update(0, 0))
+	require.NoError(t, chooser.Update(3, 10))
+	require.Equal(t, 10.0, chooser.Weight(3))
+	testWeighted(t, []float64{0, 2, 3, 10}, func() int { return chooser.ChooseFrom(rng) })
+
+	// Invalid updates leave the chooser unchanged.
+	require.ErrorIs(t, chooser.Update(1, -1), math.ErrInvalidWeights)
+	require.ErrorIs(t, chooser.Update(1, stdmath.NaN()), math.ErrInvalidWeights)
+	require.Equal(t, 2.0, chooser.Weight(1))
+	testWeighted(t, []float64{0, 2, 3, 10}, func() int { return chooser.ChooseFrom(rng) })
+
+	require.NoError(t, chooser.SetWeights([]float64{0, 0, 1}))
+	require.Equal(t, 3, chooser.Len())
+	for i := 0; i < 100; i++ {
+		require.Equal(t, 2, chooser.ChooseFrom(rng))
+	}
+
+	require.ErrorIs(t, chooser.SetWeights(nil), math.ErrInvalidWeights)
+	require.ErrorIs(t, chooser.SetWeights([]float64{0}), math.ErrInvalidWeights)
+	require.Equal(t, 3, chooser.Len())
+	require.Equal(t, 2, chooser.ChooseFrom(rng))
+
+	// Updating the last positive weight to zero is invalid.
+	require.ErrorIs(t, chooser.Update(2, 0), math.ErrInvalidWeights)
+	require.Equal(t, 2, chooser.ChooseFrom(rng))
+}
+
+func TestWeightedChooserZeroValue(t *testing.T) {
+	var chooser math.WeightedChooser[int]
+	require.Equal(t, 0, chooser.Len())
+	require.Equal(t, -1, chooser.Choose())
+
+	require.ErrorIs(t, chooser.SetWeights([]int{0}), math.ErrInvalidWeights)
+	require.Equal(t, -1, chooser.Choose())
+
+	require.NoError(t, chooser.SetWeights([]int{0, 1}))
+	require.Equal(t, 1, chooser.Choose())
+}
+
+func TestNewWeightedChooserInvalid(t *testing.T) {
+	_, err := math.NewWeightedChooser([]int(nil))
+	require.ErrorIs(t, err, math.ErrInvalidWeights)
+
+	_, err = math.NewWeightedChooser([]int{0, 0})
+	require.ErrorIs(t, err, math.ErrInvalidWeights)
+
+	_, err = math.NewWeightedChooser([]int{1, -1})
+	require.ErrorIs(t, err, math.ErrInvalidWeights)
+
+	_, err = math.NewWeightedChooser([]float64{1, stdmath.Inf(1)})
+	require.ErrorIs(t, err, math.ErrInvalidWeights)
+}
+
+// testWeighted asserts that indices drawn from choose are distributed in
+// proportion to weights.
+func testWeighted[T math.Numeric](t *testing.T, weights []T, choose func() int) {
+	t.Helper()
+
+	var total float64
+	for _, w := range weights {
+		total += float64(w)
+	}
+
+	testDiscrete(t, choose, 0, len(weights)-1, func(i int) float64 {
+		return float64(weights[i]) / total
+	})
+}